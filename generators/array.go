@@ -0,0 +1,165 @@
+package generators
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// ArrayGenerator handles test case generation for array parameters
+type ArrayGenerator struct{}
+
+// maxSampleItems is the largest array spelled out as a test case value, longer
+// arrays are only described
+const maxSampleItems = 100
+
+// GenerateTestCases generates test cases for array parameters typed
+// array[<item type>]: empty and single-item arrays, minItems/maxItems
+// boundaries, duplicate items when uniqueItems is set, and an item of the
+// wrong type
+func (g *ArrayGenerator) GenerateTestCases(ctx Context) []TestCase {
+	baseID, constraints := ctx.BaseID, ctx.Param.Constraints
+
+	itemType := arrayItemType(ctx.Param.DataType)
+	items := constraints.Items
+
+	var minItems int64
+	if constraints.MinItems != nil {
+		minItems = *constraints.MinItems
+	}
+	maxItems := constraints.MaxItems
+
+	testCases := []TestCase{
+		{
+			ID:          baseID + "_valid_input",
+			Type:        "valid",
+			Expected:    ExpectAccept,
+			Description: "Valid array of " + itemType,
+			Value:       specValue(constraints, sampleValue(ctx.Param.DataType, &constraints)),
+		},
+		{
+			ID:          baseID + "_invalid_input",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: "Invalid input for array parameter",
+			Value:       "not-an-array",
+		},
+		{
+			ID:          baseID + "_empty_array",
+			Type:        "boundary_min",
+			Expected:    expectation(minItems == 0),
+			Description: "Empty array",
+			Value:       []interface{}{},
+		},
+		{
+			ID:          baseID + "_single_item",
+			Type:        "valid",
+			Expected:    expectation(minItems <= 1 && (maxItems == nil || *maxItems >= 1)),
+			Description: "Array with a single item",
+			Value:       arrayValues(itemType, items, 1),
+		},
+	}
+
+	// Arrays of up to one item are already covered by the empty and single-item cases
+	if minItems > 1 {
+		testCases = append(testCases, arrayLengthCase(baseID+"_min_items", "boundary_min", true, itemType, items, minItems))
+	}
+	if minItems > 2 {
+		testCases = append(testCases, arrayLengthCase(baseID+"_below_min_items", "boundary_min", false, itemType, items, minItems-1))
+	}
+
+	if maxItems != nil {
+		testCases = append(testCases, arrayLengthCase(baseID+"_max_items", "boundary_max", true, itemType, items, *maxItems))
+		// No array holds more items than the largest int64
+		if *maxItems < math.MaxInt64 {
+			testCases = append(testCases, arrayLengthCase(baseID+"_above_max_items", "boundary_max", false, itemType, items, *maxItems+1))
+		}
+	}
+
+	if constraints.UniqueItems {
+		item := sampleValue(itemType, items)
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_duplicate_items",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: "Array with duplicate items (uniqueItems)",
+			Value:       []interface{}{item, item},
+		})
+	}
+
+	violation := TestCase{
+		ID:          baseID + "_item_type_violation",
+		Type:        "invalid",
+		Expected:    ExpectReject,
+		Description: "Array with an item that is not of type " + itemType,
+	}
+	wrong, ok := wrongValue(ctx.Param, itemType, nil, items)
+	violation.Value = []interface{}{wrong}
+	if _, text := wrong.(string); text && takesStrings(itemType) {
+		violation.Description = "Array with an item that violates the item constraints"
+	}
+	if ok {
+		testCases = append(testCases, violation)
+	}
+
+	return testCases
+}
+
+// arrayLengthCase builds a minItems/maxItems boundary case with n items
+func arrayLengthCase(id, caseType string, accepted bool, itemType string, items *processor.Constraints, n int64) TestCase {
+	outcome := "accepted"
+	if !accepted {
+		outcome = "rejected"
+	}
+
+	tc := TestCase{
+		ID:          id,
+		Type:        caseType,
+		Expected:    expectation(accepted),
+		Description: fmt.Sprintf("Array with %d items (%s)", n, outcome),
+	}
+	if n >= 0 && n <= maxSampleItems {
+		tc.Value = arrayValues(itemType, items, n)
+	}
+	return tc
+}
+
+// arrayItemType returns the item type of array[<item type>], or string for an
+// untyped array
+func arrayItemType(dataType string) string {
+	if strings.HasPrefix(dataType, "array[") && strings.HasSuffix(dataType, "]") {
+		return dataType[len("array[") : len(dataType)-1]
+	}
+	return "string"
+}
+
+// arrayValues builds n valid items, distinct from one another where the item
+// type allows it so the array also satisfies uniqueItems. A negative n gives
+// no items.
+func arrayValues(itemType string, items *processor.Constraints, n int64) []interface{} {
+	if n < 0 {
+		return []interface{}{}
+	}
+	values := make([]interface{}, 0, n)
+	for i := int64(0); i < n; i++ {
+		switch v := sampleValue(itemType, items).(type) {
+		case int64:
+			values = append(values, v+i)
+		case float64:
+			values = append(values, v+float64(i))
+		case string:
+			if i > 0 {
+				v += strconv.FormatInt(i, 10)
+			}
+			values = append(values, v)
+		case bool:
+			values = append(values, v == (i%2 == 0))
+		default:
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,187 @@
+package generators
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestArrayLengthCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxItems int64
+		want     map[string]int // case ID suffix to item count, -1 when the case has no value
+		absent   []string
+	}{
+		{
+			name:     "small maxItems is spelled out",
+			maxItems: 3,
+			want:     map[string]int{"_max_items": 3, "_above_max_items": 4},
+		},
+		{
+			name:     "large maxItems is only described",
+			maxItems: 1000,
+			want:     map[string]int{"_max_items": -1, "_above_max_items": -1},
+		},
+		{
+			name:     "no case above the largest int64",
+			maxItems: math.MaxInt64,
+			want:     map[string]int{"_max_items": -1},
+			absent:   []string{"_above_max_items"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("a", (&ArrayGenerator{}).GenerateTestCases(Context{
+				BaseID: "a",
+				Param: processor.ParameterCase{
+					DataType:    "array[integer]",
+					Constraints: processor.Constraints{MaxItems: int64Ptr(tt.maxItems)},
+				},
+			}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if !ok {
+					t.Errorf("%s: missing", id)
+					continue
+				}
+				items, _ := tc.Value.([]interface{})
+				if want < 0 && tc.Value != nil || want >= 0 && len(items) != want {
+					t.Errorf("%s: got %s, want %d items", id, tc.FormatValue(), want)
+				}
+			}
+			for _, id := range tt.absent {
+				if tc, ok := cases[id]; ok {
+					t.Errorf("%s: unexpected case %q", id, tc.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestArrayValuesNegativeCount(t *testing.T) {
+	if got := arrayValues("integer", nil, -1); len(got) != 0 {
+		t.Errorf("arrayValues(-1) = %v, want no items", got)
+	}
+}
+
+func TestArraySampleWithLargeMinItems(t *testing.T) {
+	tests := []struct {
+		minItems int64
+		want     int // items in the sample, -1 for no sample
+	}{
+		{minItems: 3, want: 3},
+		{minItems: maxSampleItems, want: maxSampleItems},
+		{minItems: maxSampleItems + 1, want: -1},
+		{minItems: math.MaxInt64, want: -1},
+	}
+
+	for _, tt := range tests {
+		got := sampleValue("array[string]", &processor.Constraints{MinItems: int64Ptr(tt.minItems)})
+		items, ok := got.([]interface{})
+		if tt.want < 0 && got != nil || tt.want >= 0 && (!ok || len(items) != tt.want) {
+			t.Errorf("minItems %d: got %d items (%T), want %d", tt.minItems, len(items), got, tt.want)
+		}
+	}
+}
+
+func TestArrayCases(t *testing.T) {
+	type outcome struct {
+		value    interface{}
+		expected string
+	}
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]*outcome // by case ID suffix, nil when the case is left out
+	}{
+		{
+			name:  "unconstrained array in a json body",
+			param: processor.ParameterCase{ParamIn: "body", MediaType: "application/json", DataType: "array[string]"},
+			want: map[string]*outcome{
+				"_valid_input":         {[]interface{}{"valid"}, ExpectAccept},
+				"_empty_array":         {[]interface{}{}, ExpectAccept},
+				"_single_item":         {[]interface{}{"valid"}, ExpectAccept},
+				"_item_type_violation": {[]interface{}{12345}, ExpectReject},
+				"_min_items":           nil,
+				"_max_items":           nil,
+				"_duplicate_items":     nil,
+			},
+		},
+		{
+			name:  "minItems above two",
+			param: processor.ParameterCase{DataType: "array[integer]", Constraints: processor.Constraints{MinItems: int64Ptr(3)}},
+			want: map[string]*outcome{
+				"_valid_input":         {[]interface{}{int64(1), int64(2), int64(3)}, ExpectAccept},
+				"_empty_array":         {[]interface{}{}, ExpectReject},
+				"_single_item":         {[]interface{}{int64(1)}, ExpectReject},
+				"_min_items":           {[]interface{}{int64(1), int64(2), int64(3)}, ExpectAccept},
+				"_below_min_items":     {[]interface{}{int64(1), int64(2)}, ExpectReject},
+				"_item_type_violation": {[]interface{}{"not-a-number"}, ExpectReject},
+			},
+		},
+		{
+			name:  "minItems two leaves below-min to the single item case",
+			param: processor.ParameterCase{DataType: "array[integer]", Constraints: processor.Constraints{MinItems: int64Ptr(2)}},
+			want: map[string]*outcome{
+				"_single_item":     {[]interface{}{int64(1)}, ExpectReject},
+				"_min_items":       {[]interface{}{int64(1), int64(2)}, ExpectAccept},
+				"_below_min_items": nil,
+			},
+		},
+		{
+			name:  "maxItems zero",
+			param: processor.ParameterCase{DataType: "array[boolean]", Constraints: processor.Constraints{MaxItems: int64Ptr(0)}},
+			want: map[string]*outcome{
+				"_single_item":         {[]interface{}{true}, ExpectReject},
+				"_max_items":           {[]interface{}{}, ExpectAccept},
+				"_above_max_items":     {[]interface{}{true}, ExpectReject},
+				"_empty_array":         {[]interface{}{}, ExpectAccept},
+				"_duplicate_items":     nil,
+				"_below_min_items":     nil,
+				"_item_type_violation": {[]interface{}{"not-a-boolean"}, ExpectReject},
+			},
+		},
+		{
+			name: "uniqueItems with item constraints",
+			param: processor.ParameterCase{DataType: "array[integer]", Constraints: processor.Constraints{
+				MinItems: int64Ptr(2), UniqueItems: true, Items: &processor.Constraints{Minimum: float64Ptr(10)}}},
+			want: map[string]*outcome{
+				"_min_items":       {[]interface{}{int64(10), int64(11)}, ExpectAccept},
+				"_duplicate_items": {[]interface{}{int64(10), int64(10)}, ExpectReject},
+			},
+		},
+		{
+			name:  "query array of unconstrained strings",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "array[string]"},
+			want:  map[string]*outcome{"_item_type_violation": nil},
+		},
+		{
+			name: "query array of patterned strings",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "array[string]", Constraints: processor.Constraints{
+				Items: &processor.Constraints{Pattern: "^[a-z]+$"}}},
+			want: map[string]*outcome{"_item_type_violation": {[]interface{}{"12345"}, ExpectReject}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("a", (&ArrayGenerator{}).GenerateTestCases(Context{BaseID: "a", Param: tt.param}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				if !ok || !reflect.DeepEqual(tc.Value, want.value) || tc.Expected != want.expected {
+					t.Errorf("%s: got %#v (%s), want %#v (%s)", id, tc.Value, tc.Expected, want.value, want.expected)
+				}
+			}
+		})
+	}
+}
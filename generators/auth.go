@@ -0,0 +1,162 @@
+package generators
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// Credentials used by the auth cases
+var (
+	basicCredentials = "Basic " + base64.StdEncoding.EncodeToString([]byte("user:password"))
+	bearerToken      = "Bearer " + testJWT(`{"sub":"test-user","exp":4102444800}`)
+	// expiredToken is well formed but expired on 2001-09-09
+	expiredToken = "Bearer " + testJWT(`{"sub":"test-user","exp":1000000000}`)
+)
+
+// GenerateAuthTestCases generates endpoint-level cases for the security
+// requirements of an operation: a request without credentials, and for each
+// scheme malformed credentials, an expired token (bearer, OAuth2 and OpenID
+// Connect), credentials of the wrong scheme and, per required scope, a token
+// lacking that scope. An operation that allows anonymous access gets an
+// accepted _auth_anonymous_allowed case instead of _auth_no_credentials.
+func GenerateAuthTestCases(baseID string, ep processor.EndpointCases) []TestCase {
+	var testCases []TestCase
+	if ep.AnonymousAllowed {
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_auth_anonymous_allowed",
+			Type:        "auth",
+			Expected:    ExpectAccept,
+			Description: "Request without credentials (accepted, anonymous access allowed)",
+		})
+	} else if len(ep.Security) > 0 {
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_auth_no_credentials",
+			Type:        "auth",
+			Expected:    ExpectReject,
+			Description: "Request without credentials (rejected)",
+		})
+	}
+
+	// Schemes can appear in several alternatives, each is named once
+	var names []string
+	seenScheme := make(map[string]bool)
+	for _, req := range ep.Security {
+		for _, scheme := range req.Schemes {
+			if !seenScheme[scheme.Name] {
+				seenScheme[scheme.Name] = true
+				names = append(names, scheme.Name)
+			}
+		}
+	}
+	fragments := make(map[string]string, len(names))
+	for i, fragment := range enumIDFragments(stringsToValues(names)) {
+		fragments[names[i]] = fragment
+	}
+
+	seen := make(map[string]bool)
+	for _, req := range ep.Security {
+		for _, scheme := range req.Schemes {
+			prefix := baseID + "_auth_" + fragments[scheme.Name]
+			for _, tc := range schemeTestCases(prefix, scheme) {
+				if !seen[tc.ID] {
+					seen[tc.ID] = true
+					testCases = append(testCases, tc)
+				}
+			}
+		}
+	}
+	return testCases
+}
+
+// schemeTestCases generates the rejected cases of one security scheme
+func schemeTestCases(prefix string, scheme processor.SecurityScheme) []TestCase {
+	in, name := "header", "Authorization"
+	if scheme.Type == "apiKey" {
+		in, name = scheme.In, scheme.ParamName
+	}
+	credential := func(value string) map[string]interface{} {
+		return map[string]interface{}{"in": in, "name": name, "value": value}
+	}
+
+	bearer := scheme.Type == "oauth2" || scheme.Type == "openIdConnect" ||
+		(scheme.Type == "http" && scheme.Scheme == "bearer")
+
+	// An API key is sent as a bearer token instead of where it belongs
+	malformed := "invalid-api-key"
+	wrong := map[string]interface{}{"in": "header", "name": "Authorization", "value": "Bearer test-api-key"}
+	switch {
+	case bearer:
+		malformed, wrong = "Bearer not-a-token", credential(basicCredentials)
+	case scheme.Scheme == "basic":
+		malformed, wrong = "Basic not-base64!", credential(bearerToken)
+	case scheme.Type != "apiKey":
+		malformed, wrong = capitalize(scheme.Scheme)+" invalid", credential(bearerToken)
+	}
+
+	testCases := []TestCase{{
+		ID:          prefix + "_malformed",
+		Type:        "auth",
+		Expected:    ExpectReject,
+		Description: fmt.Sprintf("Malformed %s credentials (rejected)", scheme.Name),
+		Value:       credential(malformed),
+	}}
+
+	if bearer {
+		testCases = append(testCases, TestCase{
+			ID:          prefix + "_expired",
+			Type:        "auth",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("Expired %s token (rejected)", scheme.Name),
+			Value:       credential(expiredToken),
+		})
+	}
+
+	testCases = append(testCases, TestCase{
+		ID:          prefix + "_wrong_scheme",
+		Type:        "auth",
+		Expected:    ExpectReject,
+		Description: fmt.Sprintf("Credentials of another scheme instead of %s (rejected)", scheme.Name),
+		Value:       wrong,
+	})
+
+	scopeFragments := enumIDFragments(stringsToValues(scheme.Scopes))
+	for i, missing := range scheme.Scopes {
+		granted := make([]interface{}, 0, len(scheme.Scopes)-1)
+		for _, scope := range scheme.Scopes {
+			if scope != missing {
+				granted = append(granted, scope)
+			}
+		}
+		value := credential(bearerToken)
+		value["scopes"] = granted
+		testCases = append(testCases, TestCase{
+			ID:          prefix + "_missing_scope_" + scopeFragments[i],
+			Type:        "auth",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("%s token without the %s scope (rejected)", scheme.Name, missing),
+			Value:       value,
+		})
+	}
+
+	return testCases
+}
+
+// testJWT builds a placeholder HS256 JWT carrying the given claims, for tests
+// to replace with a token signed by the API's issuer
+func testJWT(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(claims)) + "." +
+		enc.EncodeToString([]byte("signature"))
+}
+
+// capitalize upper-cases the first letter of an HTTP authentication scheme
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
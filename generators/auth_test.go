@@ -0,0 +1,141 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestAuthCases(t *testing.T) {
+	apiKey := processor.SecurityScheme{Name: "api_key", Type: "apiKey", In: "query", ParamName: "key"}
+	basic := processor.SecurityScheme{Name: "basic", Type: "http", Scheme: "basic"}
+	digest := processor.SecurityScheme{Name: "digest", Type: "http", Scheme: "digest"}
+	oauth := processor.SecurityScheme{Name: "petstore-auth", Type: "oauth2", Scopes: []string{"read:pets", "write:pets"}}
+
+	tests := []struct {
+		name string
+		ep   processor.EndpointCases
+		want []string // case ID suffixes in order
+	}{
+		{
+			name: "unsecured",
+			ep:   processor.EndpointCases{},
+			want: nil,
+		},
+		{
+			name: "anonymous access allowed",
+			ep:   processor.EndpointCases{AnonymousAllowed: true},
+			want: []string{"_auth_anonymous_allowed"},
+		},
+		{
+			name: "api key",
+			ep:   processor.EndpointCases{Security: []processor.SecurityRequirement{{Schemes: []processor.SecurityScheme{apiKey}}}},
+			want: []string{"_auth_no_credentials", "_auth_api_key_malformed", "_auth_api_key_wrong_scheme"},
+		},
+		{
+			name: "oauth2 scopes",
+			ep:   processor.EndpointCases{Security: []processor.SecurityRequirement{{Schemes: []processor.SecurityScheme{oauth}}}},
+			want: []string{
+				"_auth_no_credentials",
+				"_auth_petstore_auth_malformed",
+				"_auth_petstore_auth_expired",
+				"_auth_petstore_auth_wrong_scheme",
+				"_auth_petstore_auth_missing_scope_read_pets",
+				"_auth_petstore_auth_missing_scope_write_pets",
+			},
+		},
+		{
+			name: "optional alternatives with a repeated scheme",
+			ep: processor.EndpointCases{AnonymousAllowed: true, Security: []processor.SecurityRequirement{
+				{Schemes: []processor.SecurityScheme{basic}},
+				{Schemes: []processor.SecurityScheme{basic, digest}},
+			}},
+			want: []string{
+				"_auth_anonymous_allowed",
+				"_auth_basic_malformed",
+				"_auth_basic_wrong_scheme",
+				"_auth_digest_malformed",
+				"_auth_digest_wrong_scheme",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, tc := range GenerateAuthTestCases("a", tt.ep) {
+				got = append(got, tc.ID[len("a"):])
+				if want := expectation(tc.ID == "a_auth_anonymous_allowed"); tc.Expected != want || tc.Type != "auth" {
+					t.Errorf("%s: got %s %s, want auth %s", tc.ID, tc.Type, tc.Expected, want)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthCredentials(t *testing.T) {
+	credential := func(in, name, value string) map[string]interface{} {
+		return map[string]interface{}{"in": in, "name": name, "value": value}
+	}
+	tests := []struct {
+		scheme processor.SecurityScheme
+		want   map[string]interface{} // case ID suffix to value
+	}{
+		{
+			scheme: processor.SecurityScheme{Type: "apiKey", In: "cookie", ParamName: "session"},
+			want: map[string]interface{}{
+				"_malformed":    credential("cookie", "session", "invalid-api-key"),
+				"_wrong_scheme": credential("header", "Authorization", "Bearer test-api-key"),
+			},
+		},
+		{
+			scheme: processor.SecurityScheme{Type: "http", Scheme: "basic"},
+			want: map[string]interface{}{
+				"_malformed":    credential("header", "Authorization", "Basic not-base64!"),
+				"_wrong_scheme": credential("header", "Authorization", bearerToken),
+			},
+		},
+		{
+			scheme: processor.SecurityScheme{Type: "http", Scheme: "bearer"},
+			want: map[string]interface{}{
+				"_malformed":    credential("header", "Authorization", "Bearer not-a-token"),
+				"_expired":      credential("header", "Authorization", expiredToken),
+				"_wrong_scheme": credential("header", "Authorization", basicCredentials),
+			},
+		},
+		{
+			scheme: processor.SecurityScheme{Type: "http", Scheme: "digest"},
+			want: map[string]interface{}{
+				"_malformed":    credential("header", "Authorization", "Digest invalid"),
+				"_wrong_scheme": credential("header", "Authorization", bearerToken),
+			},
+		},
+		{
+			scheme: processor.SecurityScheme{Type: "openIdConnect", Scopes: []string{"openid"}},
+			want: map[string]interface{}{
+				"_malformed":    credential("header", "Authorization", "Bearer not-a-token"),
+				"_expired":      credential("header", "Authorization", expiredToken),
+				"_wrong_scheme": credential("header", "Authorization", basicCredentials),
+				"_missing_scope_openid": map[string]interface{}{
+					"in": "header", "name": "Authorization", "value": bearerToken, "scopes": []interface{}{},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme.Type+" "+tt.scheme.Scheme, func(t *testing.T) {
+			got := map[string]interface{}{}
+			for _, tc := range schemeTestCases("s", tt.scheme) {
+				got[tc.ID[len("s"):]] = tc.Value
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,39 +1,235 @@
 package generators
 
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// Expected outcomes of a test case
+const (
+	ExpectAccept = "accept" // the API should accept the input
+	ExpectReject = "reject" // the API should reject the input
+)
+
 // TestCase represents a generated test case
 type TestCase struct {
 	ID          string
-	Type        string // valid, invalid, boundary_min, boundary_max, enum_value
+	Type        string // valid, invalid, boundary_min, boundary_max, overflow, enum_value, upload, missing, null, empty, location, security, combination, response, auth, serialization, server
 	Description string
+	Value       interface{} // concrete input value, nil when the case does not define one
+	Expected    string      // ExpectAccept or ExpectReject
+}
+
+// FormatValue renders the input value as JSON, or "" when the case has none
+func (tc TestCase) FormatValue() string {
+	if tc.Value == nil {
+		return ""
+	}
+	// Payloads such as <script> are shown as written rather than \u003c-escaped
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(tc.Value); err != nil {
+		return fmt.Sprint(tc.Value)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// expectation maps whether an input should be accepted to an expected outcome
+func expectation(accepted bool) string {
+	if accepted {
+		return ExpectAccept
+	}
+	return ExpectReject
+}
+
+// specValue returns the example, or failing that the default, declared by the
+// schema, or fallback when it declares neither
+func specValue(c processor.Constraints, fallback interface{}) interface{} {
+	if c.Example != nil {
+		return c.Example
+	}
+	if c.Default != nil {
+		return c.Default
+	}
+	return fallback
+}
+
+// Context is what a generator knows about the parameter or body field it
+// generates cases for
+type Context struct {
+	BaseID   string                  // test ID prefix for the parameter
+	Param    processor.ParameterCase // location, requiredness, type, constraints, media type
+	Endpoint processor.EndpointCases // endpoint the parameter belongs to
 }
 
 // Generator defines the interface for test case generators
 type Generator interface {
-	GenerateTestCases(baseID string, paramType string, enumValues []interface{}) []TestCase
+	GenerateTestCases(ctx Context) []TestCase
 }
 
 // GenerateTestCasesForType returns appropriate test cases based on the data type
-func GenerateTestCasesForType(baseID string, dataType string, enumValues []interface{}) []TestCase {
-	var generator Generator
+// and the schema constraints of the parameter, using the default registry
+func GenerateTestCasesForType(baseID string, dataType string, enumValues []interface{}, constraints processor.Constraints) []TestCase {
+	return GenerateTestCasesForParameter(Context{
+		BaseID: baseID,
+		Param: processor.ParameterCase{
+			DataType:    dataType,
+			EnumValues:  enumValues,
+			Constraints: constraints,
+		},
+	})
+}
+
+// GenerateTestCasesForParameter returns the test cases every generator in the
+// default registry contributes to a parameter
+func GenerateTestCasesForParameter(ctx Context) []TestCase {
+	return DefaultRegistry.Generate(ctx)
+}
+
+// sampleValue builds a valid value of a data type such as integer,
+// array[string] or object, honouring the constraints where it can. Unions
+// such as integer|string use their first member. Arrays needing more than
+// maxSampleItems items have no sample and give nil.
+func sampleValue(dataType string, c *processor.Constraints) interface{} {
+	var constraints processor.Constraints
+	if c != nil {
+		constraints = *c
+	}
+	if i := strings.Index(dataType, "|"); i >= 0 {
+		dataType = dataType[:i]
+	}
+
+	if strings.HasPrefix(dataType, "array") {
+		n := int64(1)
+		if constraints.MinItems != nil && *constraints.MinItems > n {
+			n = *constraints.MinItems
+		}
+		if n > maxSampleItems {
+			return nil
+		}
+		return arrayValues(arrayItemType(dataType), constraints.Items, n)
+	}
 
 	switch dataType {
 	case "integer":
-		generator = &IntegerGenerator{}
+		lower, upper := integerRange(constraints)
+		return validInteger(lower, upper, constraints.MultipleOf)
 	case "number":
-		generator = &NumberGenerator{}
-	case "string":
-		generator = &StringGenerator{}
+		return validNumber(constraints)
 	case "boolean":
-		generator = &BooleanGenerator{}
+		return true
+	case "object":
+		return sampleObject(constraints)
+	case "file":
+		return uploadValue("sample.txt", "text/plain", "sample file content")
+	default:
+		value, _ := validString(constraints, stringFormatCases[constraints.Format])
+		return value
+	}
+}
+
+// wrongTypeValue returns a value that is not of the data type
+func wrongTypeValue(dataType string) interface{} {
+	switch {
+	case strings.HasPrefix(dataType, "array"):
+		return "not-an-array"
+	case dataType == "integer" || dataType == "number":
+		return "not-a-number"
+	case dataType == "boolean":
+		return "not-a-boolean"
+	case dataType == "object":
+		return "not-an-object"
+	case dataType == "file":
+		return "not-a-file"
 	default:
-		// Default to string generator for unknown types
-		generator = &StringGenerator{}
+		return 12345
+	}
+}
+
+// wrongValue returns a value a parameter or array item of the data type,
+// enum and constraints rejects for its type, see wrongUnionValue,
+// wrongStringValue and wrongTypeValue. ok is false when there is none.
+func wrongValue(param processor.ParameterCase, dataType string, enumValues []interface{}, c *processor.Constraints) (interface{}, bool) {
+	switch {
+	case strings.Contains(dataType, "|"):
+		return wrongUnionValue(param, dataType, c)
+	case takesStrings(dataType):
+		return wrongStringValue(param, dataType, enumValues, c)
+	}
+	return wrongTypeValue(dataType), true
+}
+
+// typedValues reports whether the values of a parameter keep their JSON type
+// on the wire, as in JSON request bodies. Anywhere else (path, query, header,
+// cookie, form and XML fields) values travel as text, where a number is just
+// a string of digits.
+func typedValues(param processor.ParameterCase) bool {
+	return param.ParamIn == "" || (param.ParamIn == "body" && (param.MediaType == "" || strings.Contains(param.MediaType, "json")))
+}
+
+// wrongStringValue returns a value a string of the data type, enum and
+// constraints rejects: a number where values are typed, and elsewhere the
+// number's digits as text when the enum, length, pattern or format rule them
+// out. ok is false when the digits would be accepted.
+func wrongStringValue(param processor.ParameterCase, dataType string, enumValues []interface{}, c *processor.Constraints) (interface{}, bool) {
+	if typedValues(param) {
+		return 12345, true
+	}
+	if acceptsString(dataType, enumValues, c, "12345") {
+		return nil, false
 	}
+	return "12345", true
+}
 
-	// If enum values exist, use enum generator instead
+// acceptsString reports whether a value of the data type, enum and
+// constraints accepts the text value: the type takes strings (strings,
+// unions with a string member and types the string generator falls back
+// to), the value is an enum member when there is an enum, and it satisfies
+// the length bounds, the pattern and the format
+func acceptsString(dataType string, enumValues []interface{}, c *processor.Constraints, value string) bool {
+	if !takesStrings(dataType) {
+		return false
+	}
 	if len(enumValues) > 0 {
-		generator = &EnumGenerator{}
+		for _, v := range enumValues {
+			if s, ok := v.(string); ok && s == value {
+				return true
+			}
+		}
+		return false
+	}
+	if c == nil {
+		return true
 	}
 
-	return generator.GenerateTestCases(baseID, dataType, enumValues)
+	if !withinLength(*c, value) {
+		return false
+	}
+	if c.Pattern != "" {
+		// A pattern Go cannot compile is assumed not to match
+		re, err := regexp.Compile(c.Pattern)
+		if err != nil || !re.MatchString(value) {
+			return false
+		}
+	}
+	return matchesFormat(c.Format, value)
+}
+
+// takesStrings reports whether a data type has a string member: anything but
+// numbers, booleans, objects, files and arrays, alone or in a union
+func takesStrings(dataType string) bool {
+	for _, member := range strings.Split(dataType, "|") {
+		switch {
+		case member == "integer", member == "number", member == "boolean", member == "object", member == "file":
+		case strings.HasPrefix(member, "array"):
+		default:
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,141 @@
+package generators
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestSampleValue(t *testing.T) {
+	tests := []struct {
+		dataType    string
+		constraints *processor.Constraints
+		want        interface{}
+	}{
+		{dataType: "integer", want: int64(1)},
+		{dataType: "integer", constraints: &processor.Constraints{Minimum: float64Ptr(10), Maximum: float64Ptr(20), MultipleOf: float64Ptr(4)}, want: int64(16)},
+		{dataType: "number", want: 1.5},
+		{dataType: "number", constraints: &processor.Constraints{Minimum: float64Ptr(2)}, want: 3.0},
+		{dataType: "boolean", want: true},
+		{dataType: "string", want: "valid"},
+		{dataType: "string", constraints: &processor.Constraints{Format: "uuid"}, want: "123e4567-e89b-12d3-a456-426614174000"},
+		{dataType: "string", constraints: &processor.Constraints{MinLength: int64Ptr(7)}, want: "aaaaaaa"},
+		{dataType: "integer|string", want: int64(1)},
+		{dataType: "array[integer]", want: []interface{}{int64(1)}},
+		{dataType: "array[string]", constraints: &processor.Constraints{MinItems: int64Ptr(3)}, want: []interface{}{"valid", "valid1", "valid2"}},
+		{dataType: "array[boolean]", constraints: &processor.Constraints{MinItems: int64Ptr(2)}, want: []interface{}{true, false}},
+		{dataType: "array[string]", constraints: &processor.Constraints{MinItems: int64Ptr(maxSampleItems + 1)}, want: nil},
+		{dataType: "object", want: map[string]interface{}{}},
+		{
+			dataType:    "object",
+			constraints: &processor.Constraints{Required: []string{"id", "name"}, Properties: map[string]string{"id": "integer"}},
+			want:        map[string]interface{}{"id": int64(1), "name": "valid"},
+		},
+		{dataType: "file", want: map[string]interface{}{"filename": "sample.txt", "content_type": "text/plain", "content": "sample file content"}},
+	}
+
+	for _, tt := range tests {
+		if got := sampleValue(tt.dataType, tt.constraints); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sampleValue(%q, %+v) = %#v, want %#v", tt.dataType, tt.constraints, got, tt.want)
+		}
+	}
+}
+
+func TestSpecValue(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints processor.Constraints
+		want        interface{}
+	}{
+		{name: "example", constraints: processor.Constraints{Example: "ex", Default: "def"}, want: "ex"},
+		{name: "default", constraints: processor.Constraints{Default: "def"}, want: "def"},
+		{name: "fallback", constraints: processor.Constraints{}, want: "fallback"},
+	}
+
+	for _, tt := range tests {
+		if got := specValue(tt.constraints, "fallback"); got != tt.want {
+			t.Errorf("%s: got %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: nil, want: ""},
+		{value: "text", want: `"text"`},
+		{value: int64(42), want: "42"},
+		{value: "<script>&", want: `"<script>&"`},
+		{value: map[string]interface{}{"b": 1, "a": []interface{}{true}}, want: `{"a":[true],"b":1}`},
+	}
+
+	for _, tt := range tests {
+		if got := (TestCase{Value: tt.value}).FormatValue(); got != tt.want {
+			t.Errorf("FormatValue(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWrongStringValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  interface{} // nil when no value is rejected
+	}{
+		{name: "json body", param: processor.ParameterCase{ParamIn: "body", MediaType: "application/json"}, want: 12345},
+		{name: "vendor json body", param: processor.ParameterCase{ParamIn: "body", MediaType: "application/vnd.api+json"}, want: 12345},
+		{name: "unconstrained query", param: processor.ParameterCase{ParamIn: "query"}, want: nil},
+		{name: "form field", param: processor.ParameterCase{ParamIn: "body", MediaType: "application/x-www-form-urlencoded"}, want: nil},
+		{name: "query with a pattern", param: processor.ParameterCase{ParamIn: "query", Constraints: processor.Constraints{Pattern: "^[a-z]+$"}}, want: "12345"},
+		{name: "query with an enum", param: processor.ParameterCase{ParamIn: "query", EnumValues: []interface{}{"a"}}, want: "12345"},
+		{name: "query with maxLength", param: processor.ParameterCase{ParamIn: "query", Constraints: processor.Constraints{MaxLength: int64Ptr(4)}}, want: "12345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := wrongStringValue(tt.param, "string", tt.param.EnumValues, &tt.param.Constraints)
+			if ok != (tt.want != nil) || got != tt.want {
+				t.Errorf("got %#v, %v, want %#v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+// TestEveryCaseCarriesAnOutcome checks that whatever the parameter, each
+// generated case has an expected outcome and only cases leaving the value out
+// have no value
+func TestEveryCaseCarriesAnOutcome(t *testing.T) {
+	params := []processor.ParameterCase{
+		{ParamName: "id", ParamIn: "path", Required: true, DataType: "integer", Constraints: processor.Constraints{Minimum: float64Ptr(1)}},
+		{ParamName: "q", ParamIn: "query", DataType: "string", Constraints: processor.Constraints{MaxLength: int64Ptr(5), Pattern: "^[a-z]+$"}},
+		{ParamName: "ratio", ParamIn: "query", DataType: "number", Constraints: processor.Constraints{Maximum: float64Ptr(1), MultipleOf: float64Ptr(0.25)}},
+		{ParamName: "flag", ParamIn: "header", DataType: "boolean"},
+		{ParamName: "status", ParamIn: "query", DataType: "string", EnumValues: []interface{}{"a", "b"}},
+		{ParamName: "tags", ParamIn: "query", DataType: "array[string]", Style: "form", Explode: true, Constraints: processor.Constraints{MinItems: int64Ptr(1)}},
+		{ParamName: "owner", ParamIn: "body", MediaType: "application/json", Required: true, Nullable: true, DataType: "object",
+			Constraints: processor.Constraints{Required: []string{"name"}, Properties: map[string]string{"name": "string"}}},
+		{ParamName: "photo", ParamIn: "body", MediaType: "multipart/form-data", DataType: "file"},
+		{ParamName: "u", ParamIn: "body", MediaType: "application/json", DataType: "integer|string"},
+	}
+
+	for _, param := range params {
+		t.Run(param.ParamName, func(t *testing.T) {
+			baseID := "ep." + param.ParamName
+			for _, tc := range GenerateTestCasesForParameter(Context{BaseID: baseID, Param: param}) {
+				if !strings.HasPrefix(tc.ID, baseID+"_") {
+					t.Errorf("%s: ID does not start with %s_", tc.ID, baseID)
+				}
+				if tc.Expected != ExpectAccept && tc.Expected != ExpectReject {
+					t.Errorf("%s: expected outcome %q", tc.ID, tc.Expected)
+				}
+				if tc.Value == nil && tc.Type != "missing" && tc.Type != "null" {
+					t.Errorf("%s: %s case without a value", tc.ID, tc.Type)
+				}
+			}
+		})
+	}
+}
@@ -4,22 +4,28 @@ package generators
 type BooleanGenerator struct{}
 
 // GenerateTestCases generates test cases for boolean parameters
-func (g *BooleanGenerator) GenerateTestCases(baseID string, paramType string, enumValues []interface{}) []TestCase {
+func (g *BooleanGenerator) GenerateTestCases(ctx Context) []TestCase {
 	return []TestCase{
 		{
-			ID:          baseID + "_valid_true",
+			ID:          ctx.BaseID + "_valid_true",
 			Type:        "valid",
+			Expected:    ExpectAccept,
 			Description: "Valid boolean true value",
+			Value:       true,
 		},
 		{
-			ID:          baseID + "_valid_false",
+			ID:          ctx.BaseID + "_valid_false",
 			Type:        "valid",
+			Expected:    ExpectAccept,
 			Description: "Valid boolean false value",
+			Value:       false,
 		},
 		{
-			ID:          baseID + "_invalid_input",
+			ID:          ctx.BaseID + "_invalid_input",
 			Type:        "invalid",
+			Expected:    ExpectReject,
 			Description: "Invalid input for boolean parameter",
+			Value:       "not-a-boolean",
 		},
 	}
 }
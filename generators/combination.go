@@ -0,0 +1,240 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// combinationCandidates is how many random rows are tried for each
+// combination case, the one covering the most new value tuples is kept
+const combinationCandidates = 30
+
+// maxCombinationTuples bounds the value tuples tracked for an endpoint. Above
+// it the strength is lowered, and endpoints whose pairs alone exceed it get no
+// combination cases, so the work does not grow with the number of parameters.
+const maxCombinationTuples = 100000
+
+// CombinationOptions configures combinatorial endpoint-level cases
+type CombinationOptions struct {
+	Strength int   // 2 for pairwise, n for n-wise; 0 disables combinations
+	Seed     int64 // seed of the random choices, the same seed gives the same cases
+	MaxCases int   // upper bound on the cases per endpoint, 0 for no bound
+}
+
+// CombinationParameter is a parameter taking part in combinatorial cases,
+// with the values it accepts
+type CombinationParameter struct {
+	Name   string // e.g. query.status or body.address.city
+	Values []interface{}
+}
+
+// CombinationValues picks the distinct accepted values of a parameter's
+// valid, enum and boundary cases
+func CombinationValues(testCases []TestCase) []interface{} {
+	var values []interface{}
+	seen := map[string]bool{}
+	for _, tc := range testCases {
+		if tc.Expected != ExpectAccept || tc.Value == nil {
+			continue
+		}
+		switch tc.Type {
+		case "valid", "enum_value", "boundary_min", "boundary_max":
+		default:
+			continue
+		}
+		if key := tc.FormatValue(); !seen[key] {
+			seen[key] = true
+			values = append(values, tc.Value)
+		}
+	}
+	return values
+}
+
+// GenerateCombinationTestCases generates endpoint-level cases combining the
+// values of several parameters, so every combination of values of any
+// opts.Strength parameters appears in at least one case. Rows are built
+// greedily from seeded random candidates, stopping at opts.MaxCases. Each
+// value maps the parameter names to the values to send together. The strength
+// is lowered while there are more than maxCombinationTuples value tuples to
+// cover, down to pairs.
+func GenerateCombinationTestCases(baseID string, params []CombinationParameter, opts CombinationOptions) []TestCase {
+	var usable []CombinationParameter
+	for _, p := range params {
+		if len(p.Values) > 0 {
+			usable = append(usable, p)
+		}
+	}
+	if opts.Strength < 2 || len(usable) < 2 {
+		return nil
+	}
+
+	strength := opts.Strength
+	if strength > len(usable) {
+		strength = len(usable)
+	}
+	for strength > 2 && tupleCount(usable, strength) > maxCombinationTuples {
+		strength--
+	}
+	if tupleCount(usable, strength) > maxCombinationTuples {
+		return nil
+	}
+	groups := indexCombinations(len(usable), strength)
+
+	// Every value tuple of every group of parameters starts out uncovered
+	uncovered := map[string]bool{}
+	var order []string
+	for _, group := range groups {
+		forEachValueTuple(usable, group, func(values []int) {
+			key := tupleKey(group, values)
+			uncovered[key] = true
+			order = append(order, key)
+		})
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	var rows [][]int
+	for next := 0; len(uncovered) > 0 && (opts.MaxCases <= 0 || len(rows) < opts.MaxCases); {
+		for !uncovered[order[next]] {
+			next++
+		}
+		fixed := parseTupleKey(order[next])
+
+		var best []int
+		bestScore := -1
+		for i := 0; i < combinationCandidates; i++ {
+			row := make([]int, len(usable))
+			for p := range usable {
+				if v, ok := fixed[p]; ok {
+					row[p] = v
+				} else {
+					row[p] = rng.Intn(len(usable[p].Values))
+				}
+			}
+			if score := coveredTuples(groups, row, uncovered); score > bestScore {
+				best, bestScore = row, score
+			}
+		}
+
+		for _, group := range groups {
+			delete(uncovered, tupleKey(group, pick(best, group)))
+		}
+		rows = append(rows, best)
+	}
+
+	kind := fmt.Sprintf("%d-wise", strength)
+	if strength == 2 {
+		kind = "Pairwise"
+	}
+	var testCases []TestCase
+	for i, row := range rows {
+		value := map[string]interface{}{}
+		for p, v := range row {
+			value[usable[p].Name] = usable[p].Values[v]
+		}
+		testCases = append(testCases, TestCase{
+			ID:          fmt.Sprintf("%s_combination_%03d", baseID, i+1),
+			Type:        "combination",
+			Expected:    ExpectAccept,
+			Description: fmt.Sprintf("%s combination %d of %d of accepted parameter values", kind, i+1, len(rows)),
+			Value:       value,
+		})
+	}
+	return testCases
+}
+
+// tupleCount counts the value tuples of every strength-element subset of the
+// parameters without listing them, saturating at maxCombinationTuples+1
+func tupleCount(params []CombinationParameter, strength int) int {
+	limit := maxCombinationTuples + 1
+	// counts[k] is the number of value tuples of k of the parameters seen so far
+	counts := make([]int, strength+1)
+	counts[0] = 1
+	for _, p := range params {
+		for k := strength; k > 0; k-- {
+			n := counts[k] + counts[k-1]*len(p.Values)
+			if counts[k-1] > limit/len(p.Values) || n > limit {
+				n = limit
+			}
+			counts[k] = n
+		}
+	}
+	return counts[strength]
+}
+
+// indexCombinations lists every k-element subset of 0..n-1 in lexicographic order
+func indexCombinations(n, k int) [][]int {
+	var out [][]int
+	var walk func(start int, current []int)
+	walk = func(start int, current []int) {
+		if len(current) == k {
+			out = append(out, append([]int{}, current...))
+			return
+		}
+		for i := start; i < n; i++ {
+			walk(i+1, append(current, i))
+		}
+	}
+	walk(0, nil)
+	return out
+}
+
+// forEachValueTuple calls fn with every combination of value indices of the
+// parameters in group
+func forEachValueTuple(params []CombinationParameter, group []int, fn func(values []int)) {
+	values := make([]int, len(group))
+	var walk func(i int)
+	walk = func(i int) {
+		if i == len(group) {
+			fn(values)
+			return
+		}
+		for v := range params[group[i]].Values {
+			values[i] = v
+			walk(i + 1)
+		}
+	}
+	walk(0)
+}
+
+// coveredTuples counts the uncovered tuples a row would cover
+func coveredTuples(groups [][]int, row []int, uncovered map[string]bool) int {
+	n := 0
+	for _, group := range groups {
+		if uncovered[tupleKey(group, pick(row, group))] {
+			n++
+		}
+	}
+	return n
+}
+
+// pick returns the value indices of a row for the parameters in group
+func pick(row []int, group []int) []int {
+	values := make([]int, len(group))
+	for i, p := range group {
+		values[i] = row[p]
+	}
+	return values
+}
+
+// tupleKey identifies a value tuple as param=value pairs, e.g. 0=1,3=0
+func tupleKey(group, values []int) string {
+	parts := make([]string, len(group))
+	for i, p := range group {
+		parts[i] = strconv.Itoa(p) + "=" + strconv.Itoa(values[i])
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseTupleKey reverses tupleKey into parameter and value indices
+func parseTupleKey(key string) map[int]int {
+	fixed := map[int]int{}
+	for _, part := range strings.Split(key, ",") {
+		pv := strings.SplitN(part, "=", 2)
+		p, _ := strconv.Atoi(pv[0])
+		v, _ := strconv.Atoi(pv[1])
+		fixed[p] = v
+	}
+	return fixed
+}
@@ -0,0 +1,153 @@
+package generators
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// combinationParams builds parameters p0, p1, ... with the given numbers of values
+func combinationParams(sizes ...int) []CombinationParameter {
+	params := make([]CombinationParameter, len(sizes))
+	for i, n := range sizes {
+		params[i].Name = fmt.Sprintf("p%d", i)
+		for v := 0; v < n; v++ {
+			params[i].Values = append(params[i].Values, fmt.Sprintf("v%d", v))
+		}
+	}
+	return params
+}
+
+// uncoveredTuples lists the value tuples of every strength parameters that no
+// case sends together
+func uncoveredTuples(params []CombinationParameter, strength int, testCases []TestCase) []string {
+	var missing []string
+	for _, group := range indexCombinations(len(params), strength) {
+		forEachValueTuple(params, group, func(values []int) {
+			for _, tc := range testCases {
+				row := tc.Value.(map[string]interface{})
+				covered := true
+				for i, p := range group {
+					if row[params[p].Name] != params[p].Values[values[i]] {
+						covered = false
+						break
+					}
+				}
+				if covered {
+					return
+				}
+			}
+			missing = append(missing, tupleKey(group, values))
+		})
+	}
+	return missing
+}
+
+func TestCombinationCoverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    []int
+		strength int
+		kind     string
+	}{
+		{name: "pairwise", sizes: []int{3, 2, 4, 2, 3}, strength: 2, kind: "Pairwise"},
+		{name: "3-wise", sizes: []int{3, 2, 3, 2}, strength: 3, kind: "3-wise"},
+		{name: "strength above the parameter count", sizes: []int{2, 3, 2}, strength: 5, kind: "3-wise"},
+		{name: "strength lowered to stay within the tuple bound", sizes: []int{30, 30, 30, 30, 30}, strength: 3, kind: "Pairwise"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := combinationParams(tt.sizes...)
+			cases := GenerateCombinationTestCases("c", params, CombinationOptions{Strength: tt.strength, Seed: 1})
+			if len(cases) == 0 {
+				t.Fatal("no combination cases")
+			}
+			strength := tt.strength
+			if strength > len(params) {
+				strength = len(params)
+			}
+			if tt.kind == "Pairwise" {
+				strength = 2
+			}
+			if missing := uncoveredTuples(params, strength, cases); len(missing) > 0 {
+				t.Errorf("%d of the value tuples are not covered, e.g. %s", len(missing), missing[0])
+			}
+			for i, tc := range cases {
+				if want := fmt.Sprintf("c_combination_%03d", i+1); tc.ID != want || tc.Type != "combination" || tc.Expected != ExpectAccept {
+					t.Errorf("case %d = %s %s %s, want %s combination accept", i, tc.ID, tc.Type, tc.Expected, want)
+				}
+				if !strings.HasPrefix(tc.Description, tt.kind+" combination") {
+					t.Errorf("%s: description %q, want %s", tc.ID, tc.Description, tt.kind)
+				}
+			}
+		})
+	}
+}
+
+func TestCombinationOptions(t *testing.T) {
+	params := combinationParams(4, 3, 3, 2)
+	opts := CombinationOptions{Strength: 2, Seed: 7}
+
+	first := GenerateCombinationTestCases("c", params, opts)
+	if again := GenerateCombinationTestCases("c", params, opts); !reflect.DeepEqual(first, again) {
+		t.Error("the same seed gives different cases")
+	}
+
+	opts.MaxCases = 3
+	if capped := GenerateCombinationTestCases("c", params, opts); len(capped) != 3 {
+		t.Errorf("MaxCases 3 gives %d cases", len(capped))
+	}
+
+	tests := []struct {
+		name   string
+		params []CombinationParameter
+		opts   CombinationOptions
+	}{
+		{name: "disabled", params: params, opts: CombinationOptions{}},
+		{name: "strength 1", params: params, opts: CombinationOptions{Strength: 1}},
+		{name: "one parameter with values", params: append(combinationParams(3), CombinationParameter{Name: "empty"}), opts: CombinationOptions{Strength: 2}},
+		{name: "pairs beyond the tuple bound", params: combinationParams(400, 400), opts: CombinationOptions{Strength: 2}},
+	}
+	for _, tt := range tests {
+		if cases := GenerateCombinationTestCases("c", tt.params, tt.opts); cases != nil {
+			t.Errorf("%s: got %d cases, want none", tt.name, len(cases))
+		}
+	}
+}
+
+func TestTupleCount(t *testing.T) {
+	tests := []struct {
+		sizes    []int
+		strength int
+		want     int
+	}{
+		{sizes: []int{2, 3}, strength: 2, want: 6},
+		{sizes: []int{2, 3, 4}, strength: 2, want: 6 + 8 + 12},
+		{sizes: []int{2, 3, 4}, strength: 3, want: 24},
+		{sizes: []int{1000, 1000}, strength: 2, want: maxCombinationTuples + 1},
+	}
+
+	for _, tt := range tests {
+		if got := tupleCount(combinationParams(tt.sizes...), tt.strength); got != tt.want {
+			t.Errorf("tupleCount(%v, %d) = %d, want %d", tt.sizes, tt.strength, got, tt.want)
+		}
+	}
+}
+
+func TestCombinationValues(t *testing.T) {
+	got := CombinationValues([]TestCase{
+		{Type: "valid", Expected: ExpectAccept, Value: int64(5)},
+		{Type: "boundary_min", Expected: ExpectAccept, Value: int64(1)},
+		{Type: "boundary_min", Expected: ExpectReject, Value: int64(0)},
+		{Type: "boundary_max", Expected: ExpectAccept, Value: int64(5)},
+		{Type: "enum_value", Expected: ExpectAccept, Value: "a"},
+		{Type: "missing", Expected: ExpectAccept},
+		{Type: "security", Expected: ExpectAccept, Value: "' OR '1'='1' --"},
+	})
+	want := []interface{}{int64(5), int64(1), "a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
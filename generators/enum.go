@@ -1,27 +1,199 @@
 package generators
 
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
 // EnumGenerator handles test case generation for enum parameters
 type EnumGenerator struct{}
 
-// GenerateTestCases generates test cases for enum parameters
-func (g *EnumGenerator) GenerateTestCases(baseID string, paramType string, enumValues []interface{}) []TestCase {
+// GenerateTestCases generates test cases for enum parameters: one case per
+// allowed value, a value of the right type outside the set, and a value of the
+// wrong type. Enum members may be strings, numbers, booleans or null.
+func (g *EnumGenerator) GenerateTestCases(ctx Context) []TestCase {
+	baseID, enumValues := ctx.BaseID, ctx.Param.EnumValues
+
 	var testCases []TestCase
 
 	// Generate test case for each enum value
-	for _, enumVal := range enumValues {
+	fragments := enumIDFragments(enumValues)
+	for i, enumVal := range enumValues {
+		value := enumVal
+		if value == nil {
+			// A null member is a real input, unlike a case without a value
+			value = json.RawMessage("null")
+		}
 		testCases = append(testCases, TestCase{
-			ID:          baseID + "_valid_" + enumVal.(string),
+			ID:          baseID + "_valid_" + fragments[i],
 			Type:        "enum_value",
-			Description: "Valid enum value: " + enumVal.(string),
+			Expected:    ExpectAccept,
+			Description: "Valid enum value: " + enumDisplay(enumVal),
+			Value:       value,
+		})
+	}
+
+	valueType := enumValueType(ctx.Param.DataType, enumValues)
+	if outside, ok := outOfSetValue(valueType, enumValues); ok {
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_not_in_enum",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("Valid %s that is not one of the enum values", valueType),
+			Value:       outside,
 		})
 	}
 
 	// Add invalid input test case
-	testCases = append(testCases, TestCase{
-		ID:          baseID + "_invalid_input",
-		Type:        "invalid",
-		Description: "Invalid input for enum parameter",
-	})
+	invalid, ok := interface{}("not-an-enum-value"), true
+	if valueType == "string" {
+		invalid, ok = wrongStringValue(ctx.Param, valueType, enumValues, nil)
+	}
+	if ok {
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_invalid_input",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: "Invalid input for enum parameter",
+			Value:       invalid,
+		})
+	}
 
 	return testCases
 }
+
+// enumDisplay renders an enum member for a description
+func enumDisplay(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// enumIDFragments turns enum members into test ID fragments. Anything other
+// than ASCII letters and digits becomes an underscore, other letters and digits
+// are spelled as their code point, and a leading minus sign becomes "minus".
+// Members whose fragments collide, ignoring case, get a numeric suffix.
+func enumIDFragments(values []interface{}) []string {
+	fragments := make([]string, len(values))
+	seen := map[string]bool{}
+	for i, v := range values {
+		base := enumIDFragment(v)
+		fragment := base
+		for n := 2; seen[strings.ToLower(fragment)]; n++ {
+			fragment = fmt.Sprintf("%s_%d", base, n)
+		}
+		seen[strings.ToLower(fragment)] = true
+		fragments[i] = fragment
+	}
+	return fragments
+}
+
+// enumIDFragment sanitizes a single enum member for use in a test ID
+func enumIDFragment(v interface{}) string {
+	raw := enumDisplay(v)
+	prefix := ""
+	if _, isNumber := v.(float64); isNumber && strings.HasPrefix(raw, "-") {
+		prefix, raw = "minus_", raw[1:]
+	}
+
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		case r > 127 && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			fmt.Fprintf(&b, "_u%04x_", r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+
+	// Collapse runs of underscores so "in - progress" reads in_progress
+	fragment := b.String()
+	for strings.Contains(fragment, "__") {
+		fragment = strings.ReplaceAll(fragment, "__", "_")
+	}
+	fragment = strings.Trim(fragment, "_")
+	if fragment == "" {
+		fragment = "empty"
+	}
+	return prefix + fragment
+}
+
+// enumValueType returns the JSON type of the enum members, from the declared
+// type when there is one and from the first non-null member otherwise
+func enumValueType(paramType string, values []interface{}) string {
+	switch paramType {
+	case "string", "integer", "number", "boolean":
+		return paramType
+	}
+	for _, v := range values {
+		switch val := v.(type) {
+		case string:
+			return "string"
+		case bool:
+			return "boolean"
+		case float64:
+			if val == math.Trunc(val) {
+				return "integer"
+			}
+			return "number"
+		}
+	}
+	return ""
+}
+
+// outOfSetValue picks a value of the enum's type that is not a member of it
+func outOfSetValue(valueType string, values []interface{}) (interface{}, bool) {
+	switch valueType {
+	case "string":
+		members := map[string]bool{}
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				members[s] = true
+			}
+		}
+		candidate := "not_in_enum"
+		for i := 2; members[candidate]; i++ {
+			candidate = fmt.Sprintf("not_in_enum_%d", i)
+		}
+		return candidate, true
+
+	case "integer", "number":
+		var numbers []float64
+		for _, v := range values {
+			if f, ok := v.(float64); ok {
+				numbers = append(numbers, f)
+			}
+		}
+		if len(numbers) == 0 {
+			return 1, true
+		}
+		sort.Float64s(numbers)
+		return numbers[len(numbers)-1] + 1, true
+
+	case "boolean":
+		members := map[bool]bool{}
+		for _, v := range values {
+			if b, ok := v.(bool); ok {
+				members[b] = true
+			}
+		}
+		for _, candidate := range []bool{true, false} {
+			if !members[candidate] {
+				return candidate, true
+			}
+		}
+	}
+	return nil, false
+}
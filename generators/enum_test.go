@@ -0,0 +1,126 @@
+package generators
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestEnumIDFragments(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		want   []string
+	}{
+		{name: "strings", values: []interface{}{"available", "in - progress", "v1.2", "  "}, want: []string{"available", "in_progress", "v1_2", "empty"}},
+		{name: "numbers", values: []interface{}{float64(1), float64(-2), 2.5, float64(-0.5)}, want: []string{"1", "minus_2", "2_5", "minus_0_5"}},
+		{name: "negative string keeps its dash", values: []interface{}{"-x"}, want: []string{"x"}},
+		{name: "booleans and null", values: []interface{}{true, false, nil}, want: []string{"true", "false", "null"}},
+		{name: "unicode", values: []interface{}{"café", "日本"}, want: []string{"caf_u00e9", "u65e5_u672c"}},
+		{name: "case collisions", values: []interface{}{"Active", "active", "ACTIVE"}, want: []string{"Active", "active_2", "ACTIVE_3"}},
+		{name: "sanitized collisions", values: []interface{}{"a-b", "a.b", "a_b_2"}, want: []string{"a_b", "a_b_2", "a_b_2_2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := enumIDFragments(tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnumCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataType string
+		values   []interface{}
+		in       string
+		want     map[string]interface{} // case ID suffix to value, nil when the case is left out
+	}{
+		{
+			name:     "strings in a json body",
+			dataType: "string",
+			values:   []interface{}{"a", "not_in_enum"},
+			in:       "body",
+			want: map[string]interface{}{
+				"_valid_a":           "a",
+				"_valid_not_in_enum": "not_in_enum",
+				"_not_in_enum":       "not_in_enum_2",
+				"_invalid_input":     12345,
+			},
+		},
+		{
+			name:     "strings in a query",
+			dataType: "string",
+			values:   []interface{}{"a"},
+			in:       "query",
+			want:     map[string]interface{}{"_invalid_input": "12345"},
+		},
+		{
+			name:     "integers",
+			dataType: "integer",
+			values:   []interface{}{float64(3), float64(1), float64(2)},
+			want: map[string]interface{}{
+				"_valid_3":       float64(3),
+				"_not_in_enum":   float64(4),
+				"_invalid_input": "not-an-enum-value",
+			},
+		},
+		{
+			name:   "numbers without a declared type",
+			values: []interface{}{0.5, 1.5},
+			want:   map[string]interface{}{"_valid_0_5": 0.5, "_not_in_enum": 2.5},
+		},
+		{
+			name:     "one boolean",
+			dataType: "boolean",
+			values:   []interface{}{true},
+			want:     map[string]interface{}{"_valid_true": true, "_not_in_enum": false},
+		},
+		{
+			name:     "both booleans",
+			dataType: "boolean",
+			values:   []interface{}{true, false},
+			want:     map[string]interface{}{"_not_in_enum": nil},
+		},
+		{
+			name:   "null member",
+			values: []interface{}{nil, "x"},
+			want: map[string]interface{}{
+				"_valid_null":  json.RawMessage("null"),
+				"_valid_x":     "x",
+				"_not_in_enum": "not_in_enum",
+			},
+		},
+		{
+			name:   "only null",
+			values: []interface{}{nil},
+			want:   map[string]interface{}{"_valid_null": json.RawMessage("null"), "_not_in_enum": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param := processor.ParameterCase{ParamIn: tt.in, DataType: tt.dataType, EnumValues: tt.values}
+			if tt.in == "body" {
+				param.MediaType = "application/json"
+			}
+			cases := casesByID("e", (&EnumGenerator{}).GenerateTestCases(Context{BaseID: "e", Param: param}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				if !ok || !reflect.DeepEqual(tc.Value, want) {
+					t.Errorf("%s: got %#v, want %#v", id, tc.Value, want)
+				}
+			}
+		})
+	}
+}
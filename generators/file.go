@@ -0,0 +1,264 @@
+package generators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FileGenerator handles test case generation for file uploads (multipart file
+// parts, Swagger `type: file`, arrays of files and binary request bodies)
+type FileGenerator struct{}
+
+// Size of the oversized file sent when no maxLength is declared, above the
+// upload limits servers commonly apply
+const defaultOversizedFile = 100 << 20
+
+// File extensions of common upload content types
+var fileExtensions = map[string]string{
+	"application/json": ".json",
+	"application/pdf":  ".pdf",
+	"application/xml":  ".xml",
+	"application/zip":  ".zip",
+	"image/gif":        ".gif",
+	"image/jpeg":       ".jpg",
+	"image/png":        ".png",
+	"image/svg+xml":    ".svg",
+	"image/webp":       ".webp",
+	"text/csv":         ".csv",
+	"text/plain":       ".txt",
+}
+
+// Content type and file name of the wrong MIME type and wrong extension cases,
+// which no upload declares
+const (
+	wrongFileType      = "application/x-msdownload"
+	wrongFileExtension = ".exe"
+)
+
+// uploadValue describes a file to upload. Content is the file's text, or
+// {"repeat": ..., "count": ...} for large files.
+func uploadValue(filename, contentType string, content interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"filename":     filename,
+		"content_type": contentType,
+		"content":      content,
+	}
+}
+
+// GenerateTestCases generates test cases for file parameters: a valid upload,
+// empty and oversized files, a wrong MIME type, a wrong extension and several
+// files at once. Arrays of files get the same cases with the bad file sent
+// first, and multiple files are accepted within maxItems. A raw binary body
+// has no file name and holds a single file, so it only gets the content cases.
+// Missing files are covered by the presence cases.
+func (g *FileGenerator) GenerateTestCases(ctx Context) []TestCase {
+	param := ctx.Param
+	multiple := strings.HasPrefix(param.DataType, "array")
+	raw := param.ParamIn == "body" && !strings.HasPrefix(param.MediaType, "multipart/")
+	c := param.Constraints
+	if multiple && c.Items != nil {
+		c = *c.Items
+	}
+
+	declared := len(param.FileTypes) > 0
+	contentType, ext := uploadFileType(param.FileTypes)
+	// Arrays send as many files as minItems requires, the first one varying
+	count := int64(1)
+	if min := param.Constraints.MinItems; multiple && min != nil && *min > 1 && *min < maxSampleItems {
+		count = *min
+	}
+	file := func(name string, content interface{}) interface{} {
+		value := uploadValue(name+ext, contentType, content)
+		if !multiple {
+			return value
+		}
+		files := []interface{}{value}
+		for i := int64(1); i < count; i++ {
+			files = append(files, uploadValue(fmt.Sprintf("sample%d%s", i+1, ext), contentType, "sample file content"))
+		}
+		return files
+	}
+
+	testCases := []TestCase{
+		{
+			ID:          ctx.BaseID + "_valid_upload",
+			Type:        "valid",
+			Expected:    ExpectAccept,
+			Description: "Valid file upload",
+			Value:       file("sample", "sample file content"),
+		},
+		{
+			ID:          ctx.BaseID + "_invalid_input",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: "Plain field value sent instead of a file",
+			Value:       "not-a-file",
+		},
+	}
+
+	empty := TestCase{
+		ID:          ctx.BaseID + "_empty_file",
+		Type:        "upload",
+		Expected:    ExpectAccept,
+		Description: "Upload of a zero-byte file",
+		Value:       file("empty", ""),
+	}
+	if c.MinLength != nil && *c.MinLength > 0 {
+		empty.Expected = ExpectReject
+		empty.Description = fmt.Sprintf("Upload of a zero-byte file (rejected, minLength %d)", *c.MinLength)
+	}
+	testCases = append(testCases, empty)
+
+	if max := c.MaxLength; max != nil {
+		testCases = append(testCases, TestCase{
+			ID:          ctx.BaseID + "_max_size_file",
+			Type:        "upload",
+			Expected:    ExpectAccept,
+			Description: fmt.Sprintf("File of %d bytes, the maximum size (accepted)", *max),
+			Value:       file("max", map[string]interface{}{"repeat": "A", "count": *max}),
+		})
+		// No file is larger than the largest int64
+		if *max < math.MaxInt64 {
+			testCases = append(testCases, TestCase{
+				ID:          ctx.BaseID + "_oversized_file",
+				Type:        "upload",
+				Expected:    ExpectReject,
+				Description: fmt.Sprintf("File of %d bytes, above maxLength (rejected)", *max+1),
+				Value:       file("oversized", map[string]interface{}{"repeat": "A", "count": *max + 1}),
+			})
+		}
+	} else {
+		testCases = append(testCases, TestCase{
+			ID:          ctx.BaseID + "_oversized_file",
+			Type:        "upload",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("File of %d bytes (rejected, no maxLength is declared so this checks the server's upload limit)", defaultOversizedFile),
+			Value:       file("oversized", map[string]interface{}{"repeat": "A", "count": defaultOversizedFile}),
+		})
+	}
+
+	wrongType := TestCase{
+		ID:          ctx.BaseID + "_wrong_mime_type",
+		Type:        "upload",
+		Expected:    ExpectAccept,
+		Description: "File with the " + wrongFileType + " content type (accepted, no content type is declared)",
+		Value:       file("sample", "sample file content"),
+	}
+	wrongExt := TestCase{
+		ID:          ctx.BaseID + "_wrong_extension",
+		Type:        "upload",
+		Expected:    ExpectAccept,
+		Description: "File named with the " + wrongFileExtension + " extension (accepted, no content type is declared)",
+		Value:       file("sample", "sample file content"),
+	}
+	if declared {
+		types := strings.Join(param.FileTypes, ", ")
+		wrongType.Expected = ExpectReject
+		wrongType.Description = fmt.Sprintf("File with the %s content type instead of %s (rejected)", wrongFileType, types)
+		wrongExt.Expected = ExpectReject
+		wrongExt.Description = fmt.Sprintf("File named with the %s extension instead of one for %s (rejected)", wrongFileExtension, types)
+	}
+	wrongType.Value = setUpload(wrongType.Value, "content_type", wrongFileType)
+	wrongExt.Value = setUpload(wrongExt.Value, "filename", "sample"+wrongFileExtension)
+	testCases = append(testCases, wrongType)
+	if raw {
+		return testCases
+	}
+	testCases = append(testCases, wrongExt)
+
+	// Two files sent under the part name
+	pair := []interface{}{
+		uploadValue("first"+ext, contentType, "first file content"),
+		uploadValue("second"+ext, contentType, "second file content"),
+	}
+	several := TestCase{
+		ID:          ctx.BaseID + "_multiple_files",
+		Type:        "upload",
+		Expected:    ExpectReject,
+		Description: "Two files sent where a single file is expected (rejected)",
+		Value:       pair,
+	}
+	if multiple {
+		pc := param.Constraints
+		accepted := (pc.MaxItems == nil || *pc.MaxItems >= 2) && (pc.MinItems == nil || *pc.MinItems <= 2)
+		several.Expected = expectation(accepted)
+		several.Description = "Two files sent together"
+		if pc.MaxItems != nil && *pc.MaxItems >= 2 {
+			several.Description += fmt.Sprintf(", within maxItems %d", *pc.MaxItems)
+		}
+	}
+	testCases = append(testCases, several)
+
+	if max := param.Constraints.MaxItems; multiple && max != nil && *max < maxSampleItems {
+		files := make([]interface{}, 0, *max+1)
+		for i := int64(0); i <= *max; i++ {
+			files = append(files, uploadValue(fmt.Sprintf("file%d%s", i+1, ext), contentType, "sample file content"))
+		}
+		testCases = append(testCases, TestCase{
+			ID:          ctx.BaseID + "_too_many_files",
+			Type:        "upload",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("%d files, above maxItems (rejected)", *max+1),
+			Value:       files,
+		})
+	}
+
+	return testCases
+}
+
+// setUpload changes one attribute of the uploaded file, or of the first file
+// of an array
+func setUpload(value interface{}, key string, v interface{}) interface{} {
+	switch upload := value.(type) {
+	case map[string]interface{}:
+		upload[key] = v
+	case []interface{}:
+		upload[0].(map[string]interface{})[key] = v
+	}
+	return value
+}
+
+// uploadFileType picks the content type valid files are sent as, the first
+// declared one, and the extension to name them with
+func uploadFileType(fileTypes []string) (string, string) {
+	if len(fileTypes) == 0 {
+		return "text/plain", ".txt"
+	}
+	contentType := fileTypes[0]
+	// A wildcard such as image/* is sent as a known type of its family
+	if strings.HasSuffix(contentType, "/*") {
+		family := strings.TrimSuffix(contentType, "*")
+		contentType = "application/octet-stream"
+		for _, known := range sortedFileTypes() {
+			if strings.HasPrefix(known, family) {
+				contentType = known
+				break
+			}
+		}
+	}
+	return contentType, fileExtension(contentType)
+}
+
+// sortedFileTypes lists the content types with a known extension
+func sortedFileTypes() []string {
+	types := make([]string, 0, len(fileExtensions))
+	for ct := range fileExtensions {
+		types = append(types, ct)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// fileExtension returns the extension files of a content type are named with
+func fileExtension(contentType string) string {
+	if ext, ok := fileExtensions[contentType]; ok {
+		return ext
+	}
+	if i := strings.Index(contentType, "/"); i >= 0 && contentType != "application/octet-stream" && !strings.Contains(contentType[i:], "*") {
+		// e.g. image/bmp is named .bmp
+		return "." + strings.TrimPrefix(contentType[i+1:], "x-")
+	}
+	return ".bin"
+}
@@ -0,0 +1,206 @@
+package generators
+
+import (
+	"math"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestFileSizeCases(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxLength *int64
+		want      map[string]interface{} // case ID suffix to file size, nil when the case is left out
+	}{
+		{name: "declared maxLength", maxLength: int64Ptr(10), want: map[string]interface{}{"_max_size_file": int64(10), "_oversized_file": int64(11)}},
+		{name: "largest int64", maxLength: int64Ptr(math.MaxInt64), want: map[string]interface{}{"_max_size_file": int64(math.MaxInt64), "_oversized_file": nil}},
+		{name: "no maxLength", want: map[string]interface{}{"_max_size_file": nil, "_oversized_file": defaultOversizedFile}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("f", (&FileGenerator{}).GenerateTestCases(Context{
+				BaseID: "f",
+				Param: processor.ParameterCase{ParamIn: "formData", DataType: "file", MediaType: "multipart/form-data",
+					Constraints: processor.Constraints{MaxLength: tt.maxLength}},
+			}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case %q", id, tc.Description)
+					}
+					continue
+				}
+				if !ok {
+					t.Errorf("%s: missing", id)
+					continue
+				}
+				content := tc.Value.(map[string]interface{})["content"].(map[string]interface{})
+				if content["count"] != want {
+					t.Errorf("%s: size %v, want %v", id, content["count"], want)
+				}
+			}
+		})
+	}
+}
+
+func TestFileCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]string // case ID suffix to expected outcome, "" when the case is left out
+		files map[string]int    // case ID suffix to the number of files sent
+	}{
+		{
+			name:  "multipart file of any type",
+			param: processor.ParameterCase{ParamIn: "formData", DataType: "file", MediaType: "multipart/form-data"},
+			want: map[string]string{
+				"_valid_upload":    ExpectAccept,
+				"_invalid_input":   ExpectReject,
+				"_empty_file":      ExpectAccept,
+				"_wrong_mime_type": ExpectAccept,
+				"_wrong_extension": ExpectAccept,
+				"_multiple_files":  ExpectReject,
+				"_too_many_files":  "",
+			},
+			files: map[string]int{"_valid_upload": 1, "_multiple_files": 2},
+		},
+		{
+			name:  "declared content types",
+			param: processor.ParameterCase{ParamIn: "body", DataType: "file", MediaType: "multipart/form-data", FileTypes: []string{"image/png", "image/jpeg"}},
+			want: map[string]string{
+				"_valid_upload":    ExpectAccept,
+				"_wrong_mime_type": ExpectReject,
+				"_wrong_extension": ExpectReject,
+			},
+		},
+		{
+			name:  "minLength rules out empty files",
+			param: processor.ParameterCase{ParamIn: "formData", DataType: "file", MediaType: "multipart/form-data", Constraints: processor.Constraints{MinLength: int64Ptr(1)}},
+			want:  map[string]string{"_empty_file": ExpectReject},
+		},
+		{
+			name:  "raw binary body",
+			param: processor.ParameterCase{ParamIn: "body", DataType: "file", MediaType: "application/octet-stream"},
+			want: map[string]string{
+				"_valid_upload":    ExpectAccept,
+				"_wrong_mime_type": ExpectAccept,
+				"_wrong_extension": "",
+				"_multiple_files":  "",
+			},
+		},
+		{
+			name:  "raw image body",
+			param: processor.ParameterCase{ParamIn: "body", DataType: "file", MediaType: "image/png", FileTypes: []string{"image/png"}},
+			want: map[string]string{
+				"_wrong_mime_type": ExpectReject,
+				"_wrong_extension": "",
+			},
+		},
+		{
+			name: "array of files within maxItems",
+			param: processor.ParameterCase{ParamIn: "body", DataType: "array[file]", MediaType: "multipart/form-data",
+				Constraints: processor.Constraints{MaxItems: int64Ptr(3)}},
+			want: map[string]string{
+				"_valid_upload":    ExpectAccept,
+				"_wrong_mime_type": ExpectAccept,
+				"_multiple_files":  ExpectAccept,
+				"_too_many_files":  ExpectReject,
+			},
+			files: map[string]int{"_valid_upload": 1, "_wrong_mime_type": 1, "_too_many_files": 4},
+		},
+		{
+			name: "array of at most one file",
+			param: processor.ParameterCase{ParamIn: "body", DataType: "array[file]", MediaType: "multipart/form-data",
+				Constraints: processor.Constraints{MaxItems: int64Ptr(1)}},
+			want: map[string]string{
+				"_multiple_files": ExpectReject,
+				"_too_many_files": ExpectReject,
+			},
+			files: map[string]int{"_too_many_files": 2},
+		},
+		{
+			name: "array of at least three files",
+			param: processor.ParameterCase{ParamIn: "body", DataType: "array[file]", MediaType: "multipart/form-data",
+				Constraints: processor.Constraints{MinItems: int64Ptr(3), Items: &processor.Constraints{MaxLength: int64Ptr(10)}}},
+			want: map[string]string{
+				"_valid_upload":   ExpectAccept,
+				"_multiple_files": ExpectReject,
+				"_oversized_file": ExpectReject,
+				"_too_many_files": "",
+			},
+			files: map[string]int{"_valid_upload": 3, "_oversized_file": 3, "_multiple_files": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("f", (&FileGenerator{}).GenerateTestCases(Context{BaseID: "f", Param: tt.param}))
+			for id, want := range tt.want {
+				if got := cases[id].Expected; got != want {
+					t.Errorf("%s: expected %q, want %q", id, got, want)
+				}
+			}
+			for id, want := range tt.files {
+				got := 1
+				if files, ok := cases[id].Value.([]interface{}); ok {
+					got = len(files)
+				}
+				if got != want {
+					t.Errorf("%s: %d files, want %d", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWrongFileCasesChangeTheFirstFile(t *testing.T) {
+	cases := casesByID("f", (&FileGenerator{}).GenerateTestCases(Context{
+		BaseID: "f",
+		Param: processor.ParameterCase{ParamIn: "body", DataType: "array[file]", MediaType: "multipart/form-data",
+			FileTypes: []string{"image/png"}, Constraints: processor.Constraints{MinItems: int64Ptr(2)}},
+	}))
+	want := map[string][2]string{ // case ID suffix to content type and file name of the first file
+		"_valid_upload":    {"image/png", "sample.png"},
+		"_wrong_mime_type": {wrongFileType, "sample.png"},
+		"_wrong_extension": {"image/png", "sample" + wrongFileExtension},
+	}
+	for id, w := range want {
+		files := cases[id].Value.([]interface{})
+		first := files[0].(map[string]interface{})
+		if first["content_type"] != w[0] || first["filename"] != w[1] {
+			t.Errorf("%s: first file %v %v, want %s %s", id, first["content_type"], first["filename"], w[0], w[1])
+		}
+		if second := files[1].(map[string]interface{}); second["content_type"] != "image/png" || second["filename"] != "sample2.png" {
+			t.Errorf("%s: second file %v %v, want image/png sample2.png", id, second["content_type"], second["filename"])
+		}
+	}
+}
+
+func TestUploadFileType(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileTypes []string
+		wantType  string
+		wantExt   string
+	}{
+		{"none declared", nil, "text/plain", ".txt"},
+		{"known type", []string{"application/pdf", "image/png"}, "application/pdf", ".pdf"},
+		{"wildcard", []string{"image/*"}, "image/gif", ".gif"},
+		{"wildcard without a known type", []string{"video/*"}, "application/octet-stream", ".bin"},
+		{"unknown type", []string{"image/bmp"}, "image/bmp", ".bmp"},
+		{"unknown x- type", []string{"image/x-icon"}, "image/x-icon", ".icon"},
+		{"octet-stream", []string{"application/octet-stream"}, "application/octet-stream", ".bin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, gotExt := uploadFileType(tt.fileTypes)
+			if gotType != tt.wantType || gotExt != tt.wantExt {
+				t.Errorf("uploadFileType() = %q, %q, want %q, %q", gotType, gotExt, tt.wantType, tt.wantExt)
+			}
+		})
+	}
+}
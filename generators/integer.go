@@ -1,30 +1,212 @@
 package generators
 
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"openapi-tester/spec"
+)
+
 // IntegerGenerator handles test case generation for integer parameters
 type IntegerGenerator struct{}
 
-// GenerateTestCases generates test cases for integer parameters
-func (g *IntegerGenerator) GenerateTestCases(baseID string, paramType string, enumValues []interface{}) []TestCase {
-	return []TestCase{
+// GenerateTestCases generates test cases for integer parameters. Boundary cases
+// are only generated for the bounds the schema declares: the first value
+// outside, the bound itself and the first value inside the range. The inside
+// value is left out when it is the opposite bound or lies past it, and the
+// outside value when it does not fit in an int64. A declared int32/int64
+// format adds cases just past the format's native limits.
+func (g *IntegerGenerator) GenerateTestCases(ctx Context) []TestCase {
+	baseID, constraints := ctx.BaseID, ctx.Param.Constraints
+
+	lower, upper := integerRange(constraints)
+	valid := validInteger(lower, upper, constraints.MultipleOf)
+
+	testCases := []TestCase{
 		{
 			ID:          baseID + "_valid_input",
 			Type:        "valid",
+			Expected:    ExpectAccept,
 			Description: "Valid integer input",
+			Value:       specValue(constraints, valid),
 		},
 		{
 			ID:          baseID + "_invalid_input",
 			Type:        "invalid",
+			Expected:    ExpectReject,
 			Description: "Invalid input for integer parameter",
+			Value:       "not-a-number",
 		},
-		{
+	}
+
+	if lower != nil {
+		testCases = append(testCases, TestCase{
 			ID:          baseID + "_boundary_min",
 			Type:        "boundary_min",
-			Description: "Minimum boundary value for integer",
-		},
-		{
+			Expected:    ExpectAccept,
+			Description: fmt.Sprintf("Minimum boundary value %d (accepted)", *lower),
+			Value:       *lower,
+		})
+		if *lower < math.MaxInt64 && (upper == nil || *lower+1 < *upper) {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_boundary_min_inside",
+				Type:        "boundary_min",
+				Expected:    ExpectAccept,
+				Description: fmt.Sprintf("Value %d just above the minimum (accepted)", *lower+1),
+				Value:       *lower + 1,
+			})
+		}
+		if *lower > math.MinInt64 {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_boundary_min_outside",
+				Type:        "boundary_min",
+				Expected:    ExpectReject,
+				Description: fmt.Sprintf("Value %d just below the minimum (rejected)", *lower-1),
+				Value:       *lower - 1,
+			})
+		}
+	}
+
+	if upper != nil {
+		testCases = append(testCases, TestCase{
 			ID:          baseID + "_boundary_max",
 			Type:        "boundary_max",
-			Description: "Maximum boundary value for integer",
+			Expected:    ExpectAccept,
+			Description: fmt.Sprintf("Maximum boundary value %d (accepted)", *upper),
+			Value:       *upper,
+		})
+		if *upper > math.MinInt64 && (lower == nil || *upper-1 > *lower) {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_boundary_max_inside",
+				Type:        "boundary_max",
+				Expected:    ExpectAccept,
+				Description: fmt.Sprintf("Value %d just below the maximum (accepted)", *upper-1),
+				Value:       *upper - 1,
+			})
+		}
+		if *upper < math.MaxInt64 {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_boundary_max_outside",
+				Type:        "boundary_max",
+				Expected:    ExpectReject,
+				Description: fmt.Sprintf("Value %d just above the maximum (rejected)", *upper+1),
+				Value:       *upper + 1,
+			})
+		}
+	}
+
+	if m := constraints.MultipleOf; m != nil && *m > 1 && *m == math.Trunc(*m) {
+		// One off a valid multiple, on whichever side stays in range so the
+		// value is only rejected for not being a multiple
+		if notMultiple, ok := integerNextTo(valid, lower, upper); ok {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_not_multiple_of",
+				Type:        "invalid",
+				Expected:    ExpectReject,
+				Description: fmt.Sprintf("Value %d that is not a multiple of %v", notMultiple, *m),
+				Value:       notMultiple,
+			})
+		}
+	}
+
+	switch constraints.Format {
+	case "int32":
+		testCases = append(testCases, integerOverflowCases(baseID, "int32",
+			json.Number("-2147483649"), json.Number("2147483648"))...)
+	case "int64":
+		testCases = append(testCases, integerOverflowCases(baseID, "int64",
+			json.Number("-9223372036854775809"), json.Number("9223372036854775808"))...)
+	}
+
+	return testCases
+}
+
+// integerOverflowCases generates the values just past a format's native range
+func integerOverflowCases(baseID, format string, belowMin, aboveMax json.Number) []TestCase {
+	return []TestCase{
+		{
+			ID:          baseID + "_overflow_min",
+			Type:        "overflow",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("Value %s below the %s range", belowMin, format),
+			Value:       belowMin,
+		},
+		{
+			ID:          baseID + "_overflow_max",
+			Type:        "overflow",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("Value %s above the %s range", aboveMax, format),
+			Value:       aboveMax,
 		},
 	}
 }
+
+// integerRange returns the smallest and largest accepted integers, turning
+// fractional and exclusive bounds into inclusive integer ones
+func integerRange(c processor.Constraints) (lower, upper *int64) {
+	if c.Minimum != nil {
+		min := math.Ceil(*c.Minimum)
+		if c.ExclusiveMinimum && min == *c.Minimum {
+			min++
+		}
+		v := int64(math.MinInt64)
+		if min > math.MinInt64 {
+			v = int64(min)
+		}
+		lower = &v
+	}
+	if c.Maximum != nil {
+		max := math.Floor(*c.Maximum)
+		if c.ExclusiveMaximum && max == *c.Maximum {
+			max--
+		}
+		// float64(math.MaxInt64) rounds up to 2^63, which int64 cannot hold
+		v := int64(math.MaxInt64)
+		if max < math.MaxInt64 {
+			v = int64(max)
+		}
+		upper = &v
+	}
+	return lower, upper
+}
+
+// integerNextTo returns the integer just above v, or else just below it, that
+// lies in the range. ok is false when neither does.
+func integerNextTo(v int64, lower, upper *int64) (int64, bool) {
+	if v < math.MaxInt64 && (upper == nil || v+1 <= *upper) {
+		return v + 1, true
+	}
+	if v > math.MinInt64 && (lower == nil || v-1 >= *lower) {
+		return v - 1, true
+	}
+	return 0, false
+}
+
+// validInteger picks a value inside the range, rounded up to multipleOf
+func validInteger(lower, upper *int64, multipleOf *float64) int64 {
+	var v int64 = 1
+	switch {
+	case lower != nil && upper != nil:
+		v = *lower + (*upper-*lower)/2
+	case lower != nil:
+		v = *lower
+	case upper != nil:
+		v = *upper
+	}
+
+	if multipleOf != nil && *multipleOf >= 1 && *multipleOf == math.Trunc(*multipleOf) {
+		m := int64(*multipleOf)
+		if r := v % m; r != 0 {
+			v += m - r
+			if r < 0 {
+				v -= m
+			}
+		}
+		if upper != nil && v > *upper {
+			v -= m
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,139 @@
+package generators
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestIntegerBoundaryCases(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints processor.Constraints
+		want        map[string]interface{} // case ID suffix to value, nil when the case is left out
+	}{
+		{
+			name:        "both bounds",
+			constraints: processor.Constraints{Minimum: float64Ptr(1), Maximum: float64Ptr(10)},
+			want: map[string]interface{}{
+				"_valid_input":          int64(5),
+				"_boundary_min_outside": int64(0),
+				"_boundary_min":         int64(1),
+				"_boundary_min_inside":  int64(2),
+				"_boundary_max_inside":  int64(9),
+				"_boundary_max":         int64(10),
+				"_boundary_max_outside": int64(11),
+			},
+		},
+		{
+			name:        "no bounds",
+			constraints: processor.Constraints{},
+			want: map[string]interface{}{
+				"_valid_input":          int64(1),
+				"_boundary_min":         nil,
+				"_boundary_max":         nil,
+				"_boundary_min_outside": nil,
+				"_boundary_max_outside": nil,
+			},
+		},
+		{
+			name:        "exclusive and fractional bounds are made inclusive",
+			constraints: processor.Constraints{Minimum: float64Ptr(5), ExclusiveMinimum: true, Maximum: float64Ptr(9.5)},
+			want: map[string]interface{}{
+				"_boundary_min_outside": int64(5),
+				"_boundary_min":         int64(6),
+				"_boundary_min_inside":  int64(7),
+				"_boundary_max":         int64(9),
+				"_boundary_max_outside": int64(10),
+			},
+		},
+		{
+			name:        "minimum equals maximum",
+			constraints: processor.Constraints{Minimum: float64Ptr(5), Maximum: float64Ptr(5)},
+			want: map[string]interface{}{
+				"_valid_input":          int64(5),
+				"_boundary_min":         int64(5),
+				"_boundary_min_inside":  nil,
+				"_boundary_max_inside":  nil,
+				"_boundary_min_outside": int64(4),
+				"_boundary_max_outside": int64(6),
+			},
+		},
+		{
+			name:        "inside values would be the opposite bound",
+			constraints: processor.Constraints{Minimum: float64Ptr(1), Maximum: float64Ptr(2)},
+			want:        map[string]interface{}{"_boundary_min_inside": nil, "_boundary_max_inside": nil},
+		},
+		{
+			name:        "int64 limits",
+			constraints: processor.Constraints{Minimum: float64Ptr(math.MinInt64), Maximum: float64Ptr(math.MaxInt64)},
+			want: map[string]interface{}{
+				"_boundary_min":         int64(math.MinInt64),
+				"_boundary_min_inside":  int64(math.MinInt64 + 1),
+				"_boundary_min_outside": nil,
+				"_boundary_max":         int64(math.MaxInt64),
+				"_boundary_max_inside":  int64(math.MaxInt64 - 1),
+				"_boundary_max_outside": nil,
+			},
+		},
+		{
+			name:        "bounds beyond int64 are clamped",
+			constraints: processor.Constraints{Minimum: float64Ptr(-1e30), Maximum: float64Ptr(1e30)},
+			want: map[string]interface{}{
+				"_boundary_min": int64(math.MinInt64),
+				"_boundary_max": int64(math.MaxInt64),
+			},
+		},
+		{
+			name:        "multipleOf",
+			constraints: processor.Constraints{Minimum: float64Ptr(1), Maximum: float64Ptr(12), MultipleOf: float64Ptr(5)},
+			want:        map[string]interface{}{"_valid_input": int64(10), "_not_multiple_of": int64(11)},
+		},
+		{
+			name:        "not a multiple below the valid value at the maximum",
+			constraints: processor.Constraints{Minimum: float64Ptr(1), Maximum: float64Ptr(10), MultipleOf: float64Ptr(5)},
+			want:        map[string]interface{}{"_valid_input": int64(5), "_not_multiple_of": int64(6)},
+		},
+		{
+			name:        "no value in the range that is not a multiple",
+			constraints: processor.Constraints{Minimum: float64Ptr(10), Maximum: float64Ptr(10), MultipleOf: float64Ptr(5)},
+			want:        map[string]interface{}{"_valid_input": int64(10), "_not_multiple_of": nil},
+		},
+		{
+			name:        "negative multiple",
+			constraints: processor.Constraints{Maximum: float64Ptr(-3), MultipleOf: float64Ptr(4)},
+			want:        map[string]interface{}{"_valid_input": int64(-4), "_not_multiple_of": int64(-3)},
+		},
+		{
+			name:        "int32 format",
+			constraints: processor.Constraints{Format: "int32"},
+			want: map[string]interface{}{
+				"_overflow_min": json.Number("-2147483649"),
+				"_overflow_max": json.Number("2147483648"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("i", (&IntegerGenerator{}).GenerateTestCases(Context{
+				BaseID: "i",
+				Param:  processor.ParameterCase{DataType: "integer", Constraints: tt.constraints},
+			}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				if !ok || tc.Value != want {
+					t.Errorf("%s: got %#v, want %#v", id, tc.Value, want)
+				}
+			}
+		})
+	}
+}
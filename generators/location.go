@@ -0,0 +1,104 @@
+package generators
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// LocationGenerator handles the cases that depend on how a parameter travels
+// in the request rather than on its type: URL encoding of path segments and
+// case-insensitive header names. Values are given in their wire form, so the
+// cases have their own location type.
+type LocationGenerator struct{}
+
+// GenerateTestCases generates location-specific test cases for path and header parameters
+func (g *LocationGenerator) GenerateTestCases(ctx Context) []TestCase {
+	switch ctx.Param.ParamIn {
+	case "path":
+		return pathEncodingCases(ctx)
+	case "header":
+		return headerCases(ctx)
+	}
+	return nil
+}
+
+// pathEncodingCases checks that path segments are percent-decoded before they
+// are validated. Values are given as they appear in the URL.
+func pathEncodingCases(ctx Context) []TestCase {
+	param := ctx.Param
+	valid := fmt.Sprint(specValue(param.Constraints, sampleValue(param.DataType, &param.Constraints)))
+
+	testCases := []TestCase{
+		{
+			ID:          ctx.BaseID + "_percent_encoded",
+			Type:        "location",
+			Expected:    ExpectAccept,
+			Description: "Valid value with every character percent-encoded",
+			Value:       percentEncodeAll(valid),
+		},
+	}
+
+	// Reserved characters only fit values free of format, pattern and enum
+	accepts := func(value string) bool {
+		return acceptsString(param.DataType, param.EnumValues, &param.Constraints, value)
+	}
+	testCases = append(testCases,
+		TestCase{
+			ID:          ctx.BaseID + "_encoded_space",
+			Type:        "location",
+			Expected:    expectation(accepts("a b")),
+			Description: "Value containing an encoded space",
+			Value:       url.PathEscape("a b"),
+		},
+		TestCase{
+			ID:          ctx.BaseID + "_encoded_slash",
+			Type:        "location",
+			Expected:    expectation(accepts("a/b")),
+			Description: "Value containing an encoded slash, which must stay within the segment",
+			Value:       url.PathEscape("a/b"),
+		},
+	)
+
+	return testCases
+}
+
+// headerCases checks header name case-insensitivity and whitespace trimming.
+// Values map the header name to send to its value.
+func headerCases(ctx Context) []TestCase {
+	param := ctx.Param
+	valid := fmt.Sprint(specValue(param.Constraints, sampleValue(param.DataType, &param.Constraints)))
+
+	return []TestCase{
+		{
+			ID:          ctx.BaseID + "_name_lowercase",
+			Type:        "location",
+			Expected:    ExpectAccept,
+			Description: "Header name sent in lowercase, header names are case-insensitive",
+			Value:       map[string]interface{}{strings.ToLower(param.ParamName): valid},
+		},
+		{
+			ID:          ctx.BaseID + "_name_uppercase",
+			Type:        "location",
+			Expected:    ExpectAccept,
+			Description: "Header name sent in uppercase, header names are case-insensitive",
+			Value:       map[string]interface{}{strings.ToUpper(param.ParamName): valid},
+		},
+		{
+			ID:          ctx.BaseID + "_surrounding_whitespace",
+			Type:        "location",
+			Expected:    ExpectAccept,
+			Description: "Valid value surrounded by whitespace, which is not part of the header value",
+			Value:       map[string]interface{}{param.ParamName: " " + valid + " "},
+		},
+	}
+}
+
+// percentEncodeAll percent-encodes every byte of a value
+func percentEncodeAll(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		fmt.Fprintf(&b, "%%%02X", value[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestLocationCases(t *testing.T) {
+	type outcome struct {
+		value    interface{}
+		expected string
+	}
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]*outcome // by case ID suffix, nil when the case is left out
+	}{
+		{
+			name:  "string path segment",
+			param: processor.ParameterCase{ParamName: "name", ParamIn: "path", DataType: "string"},
+			want: map[string]*outcome{
+				"_percent_encoded": {"%76%61%6C%69%64", ExpectAccept},
+				"_encoded_space":   {"a%20b", ExpectAccept},
+				"_encoded_slash":   {"a%2Fb", ExpectAccept},
+				"_name_lowercase":  nil,
+			},
+		},
+		{
+			name:  "integer path segment",
+			param: processor.ParameterCase{ParamName: "id", ParamIn: "path", DataType: "integer", Constraints: processor.Constraints{Minimum: float64Ptr(42)}},
+			want: map[string]*outcome{
+				"_percent_encoded": {"%34%32", ExpectAccept},
+				"_encoded_space":   {"a%20b", ExpectReject},
+				"_encoded_slash":   {"a%2Fb", ExpectReject},
+			},
+		},
+		{
+			name:  "path segment with a format",
+			param: processor.ParameterCase{ParamName: "id", ParamIn: "path", DataType: "string", Constraints: processor.Constraints{Format: "uuid"}},
+			want: map[string]*outcome{
+				"_encoded_space": {"a%20b", ExpectReject},
+				"_encoded_slash": {"a%2Fb", ExpectReject},
+			},
+		},
+		{
+			name:  "header",
+			param: processor.ParameterCase{ParamName: "X-Request-Id", ParamIn: "header", DataType: "string", Constraints: processor.Constraints{Example: "abc"}},
+			want: map[string]*outcome{
+				"_name_lowercase":         {map[string]interface{}{"x-request-id": "abc"}, ExpectAccept},
+				"_name_uppercase":         {map[string]interface{}{"X-REQUEST-ID": "abc"}, ExpectAccept},
+				"_surrounding_whitespace": {map[string]interface{}{"X-Request-Id": " abc "}, ExpectAccept},
+				"_percent_encoded":        nil,
+			},
+		},
+		{
+			name:  "query parameter",
+			param: processor.ParameterCase{ParamName: "q", ParamIn: "query", DataType: "string"},
+			want:  map[string]*outcome{"_percent_encoded": nil, "_name_lowercase": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("l", (&LocationGenerator{}).GenerateTestCases(Context{BaseID: "l", Param: tt.param}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				if !ok || !reflect.DeepEqual(tc.Value, want.value) || tc.Expected != want.expected {
+					t.Errorf("%s: got %#v (%s), want %#v (%s)", id, tc.Value, tc.Expected, want.value, want.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTestCasesForType(t *testing.T) {
+	enum := []interface{}{"a", "b"}
+	constraints := processor.Constraints{MinLength: int64Ptr(1)}
+	got := GenerateTestCasesForType("t", "string", enum, constraints)
+	want := GenerateTestCasesForParameter(Context{
+		BaseID: "t",
+		Param:  processor.ParameterCase{DataType: "string", EnumValues: enum, Constraints: constraints},
+	})
+	if len(got) == 0 || !reflect.DeepEqual(got, want) {
+		t.Errorf("GenerateTestCasesForType = %+v, GenerateTestCasesForParameter = %+v", got, want)
+	}
+}
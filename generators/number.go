@@ -1,30 +1,215 @@
 package generators
 
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"openapi-tester/spec"
+)
+
 // NumberGenerator handles test case generation for number (float) parameters
 type NumberGenerator struct{}
 
-// GenerateTestCases generates test cases for number parameters
-func (g *NumberGenerator) GenerateTestCases(baseID string, paramType string, enumValues []interface{}) []TestCase {
-	return []TestCase{
+// GenerateTestCases generates test cases for number parameters. Boundary cases
+// are only generated for the bounds the schema declares: the bound itself and
+// the nearest representable values inside and outside the range. With
+// multipleOf the inside value is the nearest multiple in the range, and it is
+// left out when the range holds none besides the bound. A declared
+// float/double format adds cases past the format's native limits.
+func (g *NumberGenerator) GenerateTestCases(ctx Context) []TestCase {
+	baseID, constraints := ctx.BaseID, ctx.Param.Constraints
+
+	// Steps between neighbouring values follow the precision of the format
+	next := math.Nextafter
+	if constraints.Format == "float" {
+		next = func(x, y float64) float64 {
+			return float64(math.Nextafter32(float32(x), float32(y)))
+		}
+	}
+
+	valid := validNumber(constraints)
+
+	testCases := []TestCase{
 		{
 			ID:          baseID + "_valid_input",
 			Type:        "valid",
+			Expected:    ExpectAccept,
 			Description: "Valid number input",
+			Value:       specValue(constraints, valid),
 		},
 		{
 			ID:          baseID + "_invalid_input",
 			Type:        "invalid",
+			Expected:    ExpectReject,
 			Description: "Invalid input for number parameter",
+			Value:       "not-a-number",
 		},
-		{
+	}
+
+	if constraints.Minimum != nil {
+		min := *constraints.Minimum
+		testCases = append(testCases, TestCase{
 			ID:          baseID + "_boundary_min",
 			Type:        "boundary_min",
-			Description: "Minimum boundary value for number",
-		},
-		{
+			Expected:    expectation(!constraints.ExclusiveMinimum),
+			Description: fmt.Sprintf("Minimum boundary value %v (%s)", min, acceptedUnless(constraints.ExclusiveMinimum)),
+			Value:       min,
+		})
+		if inside, ok := insideNumber(constraints, min, 1, next); ok {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_boundary_min_inside",
+				Type:        "boundary_min",
+				Expected:    ExpectAccept,
+				Description: "Nearest value above the minimum (accepted)",
+				Value:       inside,
+			})
+		}
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_boundary_min_outside",
+			Type:        "boundary_min",
+			Expected:    ExpectReject,
+			Description: "Nearest value below the minimum (rejected)",
+			Value:       next(min, math.Inf(-1)),
+		})
+	}
+
+	if constraints.Maximum != nil {
+		max := *constraints.Maximum
+		testCases = append(testCases, TestCase{
 			ID:          baseID + "_boundary_max",
 			Type:        "boundary_max",
-			Description: "Maximum boundary value for number",
+			Expected:    expectation(!constraints.ExclusiveMaximum),
+			Description: fmt.Sprintf("Maximum boundary value %v (%s)", max, acceptedUnless(constraints.ExclusiveMaximum)),
+			Value:       max,
+		})
+		if inside, ok := insideNumber(constraints, max, -1, next); ok {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_boundary_max_inside",
+				Type:        "boundary_max",
+				Expected:    ExpectAccept,
+				Description: "Nearest value below the maximum (accepted)",
+				Value:       inside,
+			})
+		}
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_boundary_max_outside",
+			Type:        "boundary_max",
+			Expected:    ExpectReject,
+			Description: "Nearest value above the maximum (rejected)",
+			Value:       next(max, math.Inf(1)),
+		})
+	}
+
+	if m := constraints.MultipleOf; m != nil && *m > 0 {
+		// Half a step off a valid multiple, on whichever side stays in range
+		// so the value is only rejected for not being a multiple
+		for _, v := range []float64{valid + *m/2, valid - *m/2} {
+			if numberInRange(constraints, v) {
+				testCases = append(testCases, TestCase{
+					ID:          baseID + "_not_multiple_of",
+					Type:        "invalid",
+					Expected:    ExpectReject,
+					Description: fmt.Sprintf("Value that is not a multiple of %v", *m),
+					Value:       v,
+				})
+				break
+			}
+		}
+	}
+
+	switch constraints.Format {
+	case "float":
+		testCases = append(testCases, numberOverflowCases(baseID, "float",
+			json.Number("-3.5e38"), json.Number("3.5e38"))...)
+	case "double":
+		testCases = append(testCases, numberOverflowCases(baseID, "double",
+			json.Number("-1.8e308"), json.Number("1.8e308"))...)
+	}
+
+	return testCases
+}
+
+// numberOverflowCases generates values beyond the largest finite magnitude of a format
+func numberOverflowCases(baseID, format string, belowMin, aboveMax json.Number) []TestCase {
+	return []TestCase{
+		{
+			ID:          baseID + "_overflow_min",
+			Type:        "overflow",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("Value %s below the %s range", belowMin, format),
+			Value:       belowMin,
+		},
+		{
+			ID:          baseID + "_overflow_max",
+			Type:        "overflow",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("Value %s above the %s range", aboveMax, format),
+			Value:       aboveMax,
 		},
 	}
 }
+
+// insideNumber returns the nearest accepted value past a bound in the
+// direction of dir (1 above a minimum, -1 below a maximum): the next multiple
+// of multipleOf when there is one, otherwise the next representable value.
+// ok is false when that value is not in the range, e.g. when minimum equals
+// maximum.
+func insideNumber(c processor.Constraints, bound float64, dir int, next func(x, y float64) float64) (float64, bool) {
+	v := next(bound, math.Inf(dir))
+	if m := c.MultipleOf; m != nil && *m > 0 {
+		// A bound that is a multiple up to rounding, such as 0.3 for 0.1,
+		// counts as one
+		q := bound / *m
+		if r := math.Round(q); math.Abs(q-r) < 1e-9 {
+			q = r
+		}
+		if dir > 0 {
+			v = (math.Floor(q) + 1) * *m
+		} else {
+			v = (math.Ceil(q) - 1) * *m
+		}
+	}
+	return v, numberInRange(c, v)
+}
+
+// numberInRange reports whether a value satisfies the minimum and maximum
+func numberInRange(c processor.Constraints, v float64) bool {
+	if min := c.Minimum; min != nil && (v < *min || (c.ExclusiveMinimum && v == *min)) {
+		return false
+	}
+	if max := c.Maximum; max != nil && (v > *max || (c.ExclusiveMaximum && v == *max)) {
+		return false
+	}
+	return true
+}
+
+// acceptedUnless describes whether a bound value itself is accepted
+func acceptedUnless(exclusive bool) string {
+	if exclusive {
+		return "rejected, the bound is exclusive"
+	}
+	return "accepted"
+}
+
+// validNumber picks a value inside the range, rounded to multipleOf
+func validNumber(c processor.Constraints) float64 {
+	v := 1.5
+	switch {
+	case c.Minimum != nil && c.Maximum != nil:
+		v = *c.Minimum + (*c.Maximum-*c.Minimum)/2
+	case c.Minimum != nil:
+		v = *c.Minimum + 1
+	case c.Maximum != nil:
+		v = *c.Maximum - 1
+	}
+
+	if m := c.MultipleOf; m != nil && *m > 0 {
+		v = math.Ceil(v / *m) * *m
+		if c.Maximum != nil && v > *c.Maximum {
+			v -= *m
+		}
+	}
+
+	return v
+}
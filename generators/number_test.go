@@ -0,0 +1,90 @@
+package generators
+
+import (
+	"math"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestNumberBoundaryCases(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints processor.Constraints
+		want        map[string]interface{} // case ID suffix to value, nil when the case is left out
+	}{
+		{
+			name:        "nearest representable values without multipleOf",
+			constraints: processor.Constraints{Minimum: float64Ptr(1), Maximum: float64Ptr(2)},
+			want: map[string]interface{}{
+				"_boundary_min_inside": math.Nextafter(1, 2),
+				"_boundary_max_inside": math.Nextafter(2, 1),
+			},
+		},
+		{
+			name:        "nearest multiple above an exclusive minimum",
+			constraints: processor.Constraints{Minimum: float64Ptr(0), ExclusiveMinimum: true, MultipleOf: float64Ptr(0.01)},
+			want:        map[string]interface{}{"_boundary_min_inside": 0.01},
+		},
+		{
+			name:        "nearest multiples inside both bounds",
+			constraints: processor.Constraints{Minimum: float64Ptr(0.3), Maximum: float64Ptr(10), MultipleOf: float64Ptr(0.1)},
+			want: map[string]interface{}{
+				"_boundary_min_inside": 4 * 0.1,
+				"_boundary_max_inside": 99 * 0.1,
+			},
+		},
+		{
+			name:        "no inside values when minimum equals maximum",
+			constraints: processor.Constraints{Minimum: float64Ptr(5), Maximum: float64Ptr(5)},
+			want: map[string]interface{}{
+				"_boundary_min_inside": nil,
+				"_boundary_max_inside": nil,
+			},
+		},
+		{
+			name:        "the nearest multiple may be the opposite bound",
+			constraints: processor.Constraints{Minimum: float64Ptr(1), Maximum: float64Ptr(2), MultipleOf: float64Ptr(1)},
+			want: map[string]interface{}{
+				"_boundary_min_inside": 2.0,
+				"_boundary_max_inside": 1.0,
+			},
+		},
+		{
+			name:        "not a multiple but inside the range",
+			constraints: processor.Constraints{Minimum: float64Ptr(0), Maximum: float64Ptr(10), MultipleOf: float64Ptr(10)},
+			want:        map[string]interface{}{"_not_multiple_of": 5.0},
+		},
+		{
+			name:        "no value in the range that is not a multiple",
+			constraints: processor.Constraints{Minimum: float64Ptr(10), Maximum: float64Ptr(10), MultipleOf: float64Ptr(10)},
+			want:        map[string]interface{}{"_not_multiple_of": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("n", (&NumberGenerator{}).GenerateTestCases(Context{
+				BaseID: "n",
+				Param:  processor.ParameterCase{DataType: "number", Constraints: tt.constraints},
+			}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				if !ok || tc.Value != want {
+					t.Errorf("%s: got %v, want %v", id, tc.Value, want)
+				}
+				if tc.Expected == ExpectAccept && !numberInRange(tt.constraints, tc.Value.(float64)) {
+					t.Errorf("%s: accepted value %v is out of range", id, tc.Value)
+				}
+			}
+		})
+	}
+}
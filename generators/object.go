@@ -0,0 +1,100 @@
+package generators
+
+import "openapi-tester/spec"
+
+// ObjectGenerator handles test case generation for object parameters and body fields
+type ObjectGenerator struct{}
+
+// GenerateTestCases generates test cases for object parameters: a missing
+// property case for each required property, an undeclared property when
+// additionalProperties is declared, and values of the wrong type
+func (g *ObjectGenerator) GenerateTestCases(ctx Context) []TestCase {
+	baseID, constraints := ctx.BaseID, ctx.Param.Constraints
+
+	testCases := []TestCase{
+		{
+			ID:          baseID + "_valid_input",
+			Type:        "valid",
+			Expected:    ExpectAccept,
+			Description: "Valid object with every required property",
+			Value:       specValue(constraints, sampleObject(constraints)),
+		},
+		{
+			ID:          baseID + "_invalid_input",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: "Invalid input for object parameter",
+			Value:       "not-an-object",
+		},
+		{
+			ID:          baseID + "_array_instead_of_object",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: "Array sent instead of an object",
+			Value:       []interface{}{},
+		},
+	}
+
+	testCases = append(testCases, TestCase{
+		ID:          baseID + "_empty_object",
+		Type:        "boundary_min",
+		Expected:    expectation(len(constraints.Required) == 0),
+		Description: "Object without any properties",
+		Value:       map[string]interface{}{},
+	})
+
+	fragments := enumIDFragments(stringsToValues(constraints.Required))
+	for i, name := range constraints.Required {
+		value := sampleObject(constraints)
+		delete(value, name)
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_missing_required_" + fragments[i],
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: "Object without required property " + name,
+			Value:       value,
+		})
+	}
+
+	if ap := constraints.AdditionalProperties; ap != nil {
+		value := sampleObject(constraints)
+		value["unexpected_property"] = "value"
+		tc := TestCase{
+			ID:          baseID + "_additional_property",
+			Type:        "valid",
+			Expected:    ExpectAccept,
+			Description: "Object with an undeclared property (additionalProperties allowed)",
+			Value:       value,
+		}
+		if !*ap {
+			tc.Type = "invalid"
+			tc.Expected = ExpectReject
+			tc.Description = "Object with an undeclared property (additionalProperties: false)"
+		}
+		testCases = append(testCases, tc)
+	}
+
+	return testCases
+}
+
+// sampleObject builds an object holding a valid value for every required property
+func sampleObject(c processor.Constraints) map[string]interface{} {
+	value := map[string]interface{}{}
+	for _, name := range c.Required {
+		dataType, ok := c.Properties[name]
+		if !ok {
+			dataType = "string"
+		}
+		value[name] = sampleValue(dataType, nil)
+	}
+	return value
+}
+
+// stringsToValues converts names for enumIDFragments, which sanitizes any JSON value
+func stringsToValues(names []string) []interface{} {
+	values := make([]interface{}, len(names))
+	for i, name := range names {
+		values[i] = name
+	}
+	return values
+}
@@ -0,0 +1,87 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestObjectCases(t *testing.T) {
+	allows := func(v bool) *bool { return &v }
+	type outcome struct {
+		value    interface{}
+		expected string
+	}
+	tests := []struct {
+		name        string
+		constraints processor.Constraints
+		want        map[string]*outcome // by case ID suffix, nil when the case is left out
+	}{
+		{
+			name: "no required properties",
+			want: map[string]*outcome{
+				"_valid_input":             {map[string]interface{}{}, ExpectAccept},
+				"_invalid_input":           {"not-an-object", ExpectReject},
+				"_array_instead_of_object": {[]interface{}{}, ExpectReject},
+				"_empty_object":            {map[string]interface{}{}, ExpectAccept},
+				"_additional_property":     nil,
+			},
+		},
+		{
+			name: "required properties",
+			constraints: processor.Constraints{
+				Required:   []string{"id", "first-name"},
+				Properties: map[string]string{"id": "integer", "first-name": "string", "age": "integer"},
+			},
+			want: map[string]*outcome{
+				"_valid_input":                 {map[string]interface{}{"id": int64(1), "first-name": "valid"}, ExpectAccept},
+				"_empty_object":                {map[string]interface{}{}, ExpectReject},
+				"_missing_required_id":         {map[string]interface{}{"first-name": "valid"}, ExpectReject},
+				"_missing_required_first_name": {map[string]interface{}{"id": int64(1)}, ExpectReject},
+				"_missing_required_age":        nil,
+				"_additional_property":         nil,
+			},
+		},
+		{
+			name:        "declared example",
+			constraints: processor.Constraints{Required: []string{"id"}, Example: map[string]interface{}{"id": float64(7)}},
+			want:        map[string]*outcome{"_valid_input": {map[string]interface{}{"id": float64(7)}, ExpectAccept}},
+		},
+		{
+			name:        "additionalProperties false",
+			constraints: processor.Constraints{Required: []string{"id"}, Properties: map[string]string{"id": "boolean"}, AdditionalProperties: allows(false)},
+			want: map[string]*outcome{
+				"_additional_property": {map[string]interface{}{"id": true, "unexpected_property": "value"}, ExpectReject},
+			},
+		},
+		{
+			name:        "additionalProperties true",
+			constraints: processor.Constraints{AdditionalProperties: allows(true)},
+			want: map[string]*outcome{
+				"_additional_property": {map[string]interface{}{"unexpected_property": "value"}, ExpectAccept},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("o", (&ObjectGenerator{}).GenerateTestCases(Context{
+				BaseID: "o",
+				Param:  processor.ParameterCase{DataType: "object", Constraints: tt.constraints},
+			}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				if !ok || !reflect.DeepEqual(tc.Value, want.value) || tc.Expected != want.expected {
+					t.Errorf("%s: got %#v (%s), want %#v (%s)", id, tc.Value, tc.Expected, want.value, want.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package generators
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// patternSample derives a string matching a schema pattern. Only patterns Go's
+// regexp package can compile are supported, and the sample is checked against
+// the pattern before it is returned.
+func patternSample(pattern string) (string, bool) {
+	return patternSampleWithin(pattern, nil, nil)
+}
+
+// patternSampleWithin derives a string matching a schema pattern whose length
+// lies within minLength and maxLength. The repeated parts of the pattern (*,
+// + and open-ended {n,}) are written once more on each try until the sample
+// is long enough; ok is false when no try fits the bounds.
+func patternSampleWithin(pattern string, minLength, maxLength *int64) (string, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	parsed = parsed.Simplify()
+
+	previous := -1
+	for extra := 0; ; extra++ {
+		var b strings.Builder
+		writePatternSample(&b, parsed, extra)
+		sample := b.String()
+		n := utf8.RuneCountInString(sample)
+		if n == previous || n > maxSampleLength || (maxLength != nil && int64(n) > *maxLength) {
+			return "", false
+		}
+		previous = n
+		if re.MatchString(sample) && (minLength == nil || int64(n) >= *minLength) {
+			return sample, true
+		}
+	}
+}
+
+// patternViolation finds a short string that does not match a schema pattern.
+// Patterns are unanchored, so the string must not contain any match.
+func patternViolation(pattern string) (string, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+	for _, candidate := range []string{"!", "", " ", "0", "a", "A", "~", "-"} {
+		if !re.MatchString(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// writePatternSample writes the shortest string produced by the first branch of
+// every alternation, taking the minimum number of repetitions plus extra for
+// the unbounded ones
+func writePatternSample(b *strings.Builder, re *syntax.Regexp, extra int) {
+	// Nested repetitions grow quickly, stop once the sample is too long to use
+	if b.Len() > utf8.UTFMax*maxSampleLength {
+		return
+	}
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(classSample(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a')
+	case syntax.OpCapture:
+		writePatternSample(b, re.Sub[0], extra)
+	case syntax.OpStar:
+		for i := 0; i < extra; i++ {
+			writePatternSample(b, re.Sub[0], extra)
+		}
+	case syntax.OpPlus:
+		for i := 0; i <= extra; i++ {
+			writePatternSample(b, re.Sub[0], extra)
+		}
+	case syntax.OpRepeat:
+		n := re.Min
+		if re.Max == -1 {
+			n += extra
+		}
+		for i := 0; i < n; i++ {
+			writePatternSample(b, re.Sub[0], extra)
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writePatternSample(b, sub, extra)
+		}
+	case syntax.OpAlternate:
+		writePatternSample(b, re.Sub[0], extra)
+	}
+}
+
+// classSample picks a readable rune from a character class given as
+// [lo, hi] range pairs, preferring letters and digits
+func classSample(ranges []rune) rune {
+	for _, preferred := range []rune{'a', 'A', '0'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= preferred && preferred <= ranges[i+1] {
+				return preferred
+			}
+		}
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r-ranges[i] < 256; r++ {
+			if unicode.IsPrint(r) && !unicode.IsSpace(r) {
+				return r
+			}
+		}
+	}
+	if len(ranges) > 0 {
+		return ranges[0]
+	}
+	return 'a'
+}
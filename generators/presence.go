@@ -0,0 +1,119 @@
+package generators
+
+import (
+	"encoding/json"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// PresenceGenerator handles the cases every parameter gets whatever its type
+type PresenceGenerator struct{}
+
+// GenerateTestCases generates the cases for leaving a parameter or
+// body field out, sending it as null and sending it empty. What each case means
+// depends on where the parameter is sent:
+//
+//   - path: omitting the parameter or leaving the segment empty targets a
+//     different route, so both are rejected, and the empty segment is left to
+//     the type cases when they already send it
+//   - query, header, cookie, formData: omitting the parameter is accepted unless
+//     it is required, and sending it with an empty value is a separate case
+//     unless the type cases already send it empty (_empty_string for strings,
+//     an empty enum member)
+//   - body: omitting the field is accepted unless it is required, and null is
+//     accepted only for nullable fields of JSON bodies
+func (g *PresenceGenerator) GenerateTestCases(ctx Context) []TestCase {
+	baseID, param := ctx.BaseID, ctx.Param
+	if param.ParamIn == "path" {
+		testCases := []TestCase{{
+			ID:          baseID + "_missing",
+			Type:        "missing",
+			Expected:    ExpectReject,
+			Description: "Path parameter omitted, the request targets a different route",
+		}}
+		if emptyValueCovered(param) {
+			return testCases
+		}
+		return append(testCases, TestCase{
+			ID:          baseID + "_empty",
+			Type:        "empty",
+			Expected:    ExpectReject,
+			Description: "Empty path segment",
+			Value:       "",
+		})
+	}
+
+	missing := TestCase{
+		ID:          baseID + "_missing",
+		Type:        "missing",
+		Expected:    expectation(!param.Required),
+		Description: presenceLocation(param.ParamIn) + " omitted (optional)",
+	}
+	if param.Required {
+		missing.Description = presenceLocation(param.ParamIn) + " omitted (required)"
+	}
+	testCases := []TestCase{missing}
+
+	if param.ParamIn == "body" {
+		// Form and multipart bodies have no way to send null
+		if strings.Contains(param.MediaType, "json") {
+			null := TestCase{
+				ID:          baseID + "_null",
+				Type:        "null",
+				Expected:    ExpectReject,
+				Description: "Field sent as null (not nullable)",
+				Value:       json.RawMessage("null"),
+			}
+			if param.Nullable {
+				null.Expected = ExpectAccept
+				null.Description = "Field sent as null (nullable)"
+			}
+			testCases = append(testCases, null)
+		}
+		return testCases
+	}
+	if emptyValueCovered(param) {
+		return testCases
+	}
+
+	empty := TestCase{
+		ID:          baseID + "_empty",
+		Type:        "empty",
+		Expected:    expectation(acceptsString(param.DataType, param.EnumValues, &param.Constraints, "")),
+		Description: presenceLocation(param.ParamIn) + " sent with an empty value",
+		Value:       "",
+	}
+	return append(testCases, empty)
+}
+
+// emptyValueCovered reports whether the type cases of a parameter already
+// send it with an empty value: types with a string member get _empty_string,
+// and enums with an empty member send it
+func emptyValueCovered(param processor.ParameterCase) bool {
+	if len(param.EnumValues) > 0 {
+		for _, v := range param.EnumValues {
+			if v == "" {
+				return true
+			}
+		}
+		return false
+	}
+	return takesStrings(param.DataType)
+}
+
+// presenceLocation names what is left out in a case description
+func presenceLocation(paramIn string) string {
+	switch paramIn {
+	case "header":
+		return "Header"
+	case "cookie":
+		return "Cookie"
+	case "body":
+		return "Body field"
+	case "formData":
+		return "Form field"
+	default:
+		return "Query parameter"
+	}
+}
@@ -0,0 +1,113 @@
+package generators
+
+import (
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestPresenceEmptyValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		param     processor.ParameterCase
+		wantEmpty bool
+	}{
+		{name: "string path parameter", param: processor.ParameterCase{ParamIn: "path", DataType: "string"}},
+		{name: "integer path parameter", param: processor.ParameterCase{ParamIn: "path", DataType: "integer"}, wantEmpty: true},
+		{name: "string query parameter", param: processor.ParameterCase{ParamIn: "query", DataType: "string"}},
+		{name: "integer query parameter", param: processor.ParameterCase{ParamIn: "query", DataType: "integer"}, wantEmpty: true},
+		{name: "enum without an empty member", param: processor.ParameterCase{ParamIn: "header", DataType: "string", EnumValues: []interface{}{"a"}}, wantEmpty: true},
+		{name: "enum with an empty member", param: processor.ParameterCase{ParamIn: "header", DataType: "string", EnumValues: []interface{}{"a", ""}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("p", (&PresenceGenerator{}).GenerateTestCases(Context{BaseID: "p", Param: tt.param}))
+			if _, ok := cases["_empty"]; ok != tt.wantEmpty {
+				t.Errorf("_empty generated = %v, want %v", ok, tt.wantEmpty)
+			}
+			if _, ok := cases["_missing"]; !ok {
+				t.Error("_missing not generated")
+			}
+		})
+	}
+}
+
+func TestEmptyValueNotSentTwice(t *testing.T) {
+	for _, in := range []string{"path", "query", "header", "cookie"} {
+		var empties []string
+		for _, tc := range GenerateTestCasesForParameter(Context{
+			BaseID: "p",
+			Param:  processor.ParameterCase{ParamIn: in, DataType: "string", Required: true},
+		}) {
+			if tc.Value == "" {
+				empties = append(empties, tc.ID)
+			}
+		}
+		if len(empties) != 1 {
+			t.Errorf("%s: empty value sent by %v, want one case", in, empties)
+		}
+	}
+}
+
+func TestPresenceCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]string // case ID suffix to expected outcome, "" when the case is left out
+	}{
+		{
+			name:  "required path parameter",
+			param: processor.ParameterCase{ParamIn: "path", Required: true, DataType: "integer"},
+			want:  map[string]string{"_missing": ExpectReject, "_empty": ExpectReject, "_null": ""},
+		},
+		{
+			name:  "optional query parameter",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "integer"},
+			want:  map[string]string{"_missing": ExpectAccept, "_empty": ExpectReject, "_null": ""},
+		},
+		{
+			name:  "required header",
+			param: processor.ParameterCase{ParamIn: "header", Required: true, DataType: "boolean"},
+			want:  map[string]string{"_missing": ExpectReject, "_empty": ExpectReject},
+		},
+		{
+			name:  "enum with an empty member",
+			param: processor.ParameterCase{ParamIn: "cookie", DataType: "integer", EnumValues: []interface{}{float64(1), ""}},
+			want:  map[string]string{"_missing": ExpectAccept, "_empty": ""},
+		},
+		{
+			name:  "required json body field",
+			param: processor.ParameterCase{ParamIn: "body", MediaType: "application/json", Required: true, DataType: "string"},
+			want:  map[string]string{"_missing": ExpectReject, "_null": ExpectReject, "_empty": ""},
+		},
+		{
+			name:  "nullable json body field",
+			param: processor.ParameterCase{ParamIn: "body", MediaType: "application/merge-patch+json", Nullable: true, DataType: "object"},
+			want:  map[string]string{"_missing": ExpectAccept, "_null": ExpectAccept},
+		},
+		{
+			name:  "form body field",
+			param: processor.ParameterCase{ParamIn: "body", MediaType: "application/x-www-form-urlencoded", Nullable: true, DataType: "string"},
+			want:  map[string]string{"_missing": ExpectAccept, "_null": "", "_empty": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("p", (&PresenceGenerator{}).GenerateTestCases(Context{BaseID: "p", Param: tt.param}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == "" {
+					if ok {
+						t.Errorf("%s: unexpected case %q", id, tc.Description)
+					}
+					continue
+				}
+				if !ok || tc.Expected != want {
+					t.Errorf("%s: got %q, want %q", id, tc.Expected, want)
+				}
+			}
+		})
+	}
+}
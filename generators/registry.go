@@ -0,0 +1,230 @@
+package generators
+
+import (
+	"sort"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// Names of the built-in registrations, for Registry.Disable
+const (
+	BuiltinEnum          = "enum"
+	BuiltinInteger       = "integer"
+	BuiltinNumber        = "number"
+	BuiltinString        = "string"
+	BuiltinBoolean       = "boolean"
+	BuiltinUnion         = "union"
+	BuiltinFile          = "file"
+	BuiltinArray         = "array"
+	BuiltinObject        = "object"
+	BuiltinPresence      = "presence"
+	BuiltinLocation      = "location"
+	BuiltinSecurity      = "security"
+	BuiltinSerialization = "serialization"
+)
+
+// Priorities of the built-in registrations. Enums take precedence over the
+// type of their values, arrays of files are uploads rather than arrays, and
+// the string generator handles anything no other generator claims.
+// Location, serialization and presence cases follow the type cases.
+const (
+	PriorityEnum          = 100
+	PriorityFile          = 20
+	PriorityType          = 10
+	PriorityFallback      = 0
+	PriorityLocation      = -10
+	PrioritySerialization = -15
+	PriorityPresence      = -20
+	PrioritySecurity      = -30
+)
+
+// Matcher decides whether a registration handles a parameter
+type Matcher func(param processor.ParameterCase) bool
+
+// Registration ties a generator to the parameters it handles. Among the
+// matching exclusive registrations only the one with the highest priority
+// contributes cases, every matching additive (non-exclusive) registration
+// contributes as well. Cases are ordered by priority, then registration order,
+// and a case whose ID was already produced is dropped.
+type Registration struct {
+	Name      string
+	Generator Generator
+	Match     Matcher // nil matches every parameter
+	Priority  int
+	Exclusive bool
+}
+
+// Registry selects the generators contributing test cases to a parameter
+type Registry struct {
+	registrations []Registration
+	disabled      map[string]bool
+}
+
+// NewRegistry creates a registry holding the built-in generators
+func NewRegistry() *Registry {
+	r := &Registry{disabled: map[string]bool{}}
+	r.Register(Registration{Name: BuiltinEnum, Generator: &EnumGenerator{}, Match: MatchEnum(), Priority: PriorityEnum, Exclusive: true})
+	r.Register(Registration{Name: BuiltinInteger, Generator: &IntegerGenerator{}, Match: MatchType("integer"), Priority: PriorityType, Exclusive: true})
+	r.Register(Registration{Name: BuiltinNumber, Generator: &NumberGenerator{}, Match: MatchType("number"), Priority: PriorityType, Exclusive: true})
+	r.Register(Registration{Name: BuiltinBoolean, Generator: &BooleanGenerator{}, Match: MatchType("boolean"), Priority: PriorityType, Exclusive: true})
+	r.Register(Registration{Name: BuiltinFile, Generator: &FileGenerator{}, Match: MatchType("file", "array[file]"), Priority: PriorityFile, Exclusive: true})
+	r.Register(Registration{Name: BuiltinArray, Generator: &ArrayGenerator{}, Match: MatchType("array"), Priority: PriorityType, Exclusive: true})
+	r.Register(Registration{Name: BuiltinObject, Generator: &ObjectGenerator{}, Match: MatchType("object"), Priority: PriorityType, Exclusive: true})
+	r.Register(Registration{Name: BuiltinUnion, Generator: &UnionGenerator{}, Match: MatchUnion(), Priority: PriorityType, Exclusive: true})
+	// Strings and unknown types
+	r.Register(Registration{Name: BuiltinString, Generator: &StringGenerator{}, Priority: PriorityFallback, Exclusive: true})
+	r.Register(Registration{Name: BuiltinLocation, Generator: &LocationGenerator{}, Match: MatchLocation("path", "header"), Priority: PriorityLocation})
+	r.Register(Registration{Name: BuiltinSerialization, Generator: &SerializationGenerator{}, Match: MatchLocation("path", "query", "header", "cookie", "formData"), Priority: PrioritySerialization})
+	r.Register(Registration{Name: BuiltinPresence, Generator: &PresenceGenerator{}, Priority: PriorityPresence})
+	// Security payloads are opt-in
+	r.Register(Registration{Name: BuiltinSecurity, Generator: &SecurityGenerator{}, Priority: PrioritySecurity})
+	r.Disable(BuiltinSecurity)
+	return r
+}
+
+// Register adds a generator to the registry
+func (r *Registry) Register(reg Registration) {
+	r.registrations = append(r.registrations, reg)
+}
+
+// Disable stops the registrations with the given name from contributing,
+// e.g. Disable(BuiltinString)
+func (r *Registry) Disable(name string) {
+	r.disabled[name] = true
+}
+
+// Enable lets the registrations with the given name contribute again, e.g.
+// Enable(BuiltinSecurity) for the opt-in security payloads
+func (r *Registry) Enable(name string) {
+	delete(r.disabled, name)
+}
+
+// Generate collects the test cases of every registration handling the parameter
+func (r *Registry) Generate(ctx Context) []TestCase {
+	var matching []Registration
+	for _, reg := range r.registrations {
+		if !r.disabled[reg.Name] && (reg.Match == nil || reg.Match(ctx.Param)) {
+			matching = append(matching, reg)
+		}
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].Priority > matching[j].Priority
+	})
+
+	var testCases []TestCase
+	seen := map[string]bool{}
+	exclusiveUsed := false
+	for _, reg := range matching {
+		if reg.Exclusive {
+			if exclusiveUsed {
+				continue
+			}
+			exclusiveUsed = true
+		}
+		for _, tc := range reg.Generator.GenerateTestCases(ctx) {
+			if !seen[tc.ID] {
+				seen[tc.ID] = true
+				testCases = append(testCases, tc)
+			}
+		}
+	}
+	return testCases
+}
+
+// DefaultRegistry is the registry used by GenerateTestCasesForParameter
+var DefaultRegistry = NewRegistry()
+
+// Register adds a generator to the default registry
+func Register(reg Registration) {
+	DefaultRegistry.Register(reg)
+}
+
+// Disable stops the registrations with the given name in the default registry
+// from contributing
+func Disable(name string) {
+	DefaultRegistry.Disable(name)
+}
+
+// Enable lets the registrations with the given name in the default registry
+// contribute again
+func Enable(name string) {
+	DefaultRegistry.Enable(name)
+}
+
+// MatchType matches parameters of the given data types. "array" matches every
+// array[<item type>].
+func MatchType(types ...string) Matcher {
+	return func(param processor.ParameterCase) bool {
+		for _, t := range types {
+			if param.DataType == t || (t == "array" && strings.HasPrefix(param.DataType, "array")) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchUnion matches parameters of several types, such as integer|string
+func MatchUnion() Matcher {
+	return func(param processor.ParameterCase) bool {
+		return strings.Contains(param.DataType, "|")
+	}
+}
+
+// MatchFormat matches parameters declaring one of the given formats
+func MatchFormat(formats ...string) Matcher {
+	return func(param processor.ParameterCase) bool {
+		for _, f := range formats {
+			if param.Constraints.Format == f {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchExtension matches parameters carrying a vendor extension such as
+// x-country-code, compared case-insensitively
+func MatchExtension(name string) Matcher {
+	return func(param processor.ParameterCase) bool {
+		for ext := range param.Extensions {
+			if strings.EqualFold(ext, name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchLocation matches parameters sent in one of the given locations (path,
+// query, header, cookie, body, formData)
+func MatchLocation(locations ...string) Matcher {
+	return func(param processor.ParameterCase) bool {
+		for _, in := range locations {
+			if param.ParamIn == in {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchEnum matches parameters restricted to a list of values
+func MatchEnum() Matcher {
+	return func(param processor.ParameterCase) bool {
+		return len(param.EnumValues) > 0
+	}
+}
+
+// MatchAll matches parameters matched by every one of the matchers
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(param processor.ParameterCase) bool {
+		for _, m := range matchers {
+			if !m(param) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,193 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+// stubGenerator returns one case per suffix, with the generator's name as
+// description so tests can tell which generator produced a case
+type stubGenerator struct {
+	name     string
+	suffixes []string
+}
+
+func (g *stubGenerator) GenerateTestCases(ctx Context) []TestCase {
+	var testCases []TestCase
+	for _, s := range g.suffixes {
+		testCases = append(testCases, TestCase{ID: ctx.BaseID + s, Description: g.name, Expected: ExpectAccept})
+	}
+	return testCases
+}
+
+func TestRegistryGenerate(t *testing.T) {
+	stub := func(name string, suffixes ...string) *stubGenerator {
+		return &stubGenerator{name: name, suffixes: suffixes}
+	}
+	tests := []struct {
+		name          string
+		registrations []Registration
+		disable       []string
+		want          []string // ID suffix and generator of each case, in order
+	}{
+		{
+			name: "highest priority exclusive registration wins",
+			registrations: []Registration{
+				{Name: "low", Generator: stub("low", "_a"), Priority: 1, Exclusive: true},
+				{Name: "high", Generator: stub("high", "_b"), Priority: 5, Exclusive: true},
+			},
+			want: []string{"_b high"},
+		},
+		{
+			name: "registration order breaks priority ties",
+			registrations: []Registration{
+				{Name: "first", Generator: stub("first", "_a"), Exclusive: true},
+				{Name: "second", Generator: stub("second", "_b"), Exclusive: true},
+			},
+			want: []string{"_a first"},
+		},
+		{
+			name: "additive registrations all contribute in priority order",
+			registrations: []Registration{
+				{Name: "late", Generator: stub("late", "_late"), Priority: -10},
+				{Name: "type", Generator: stub("type", "_type"), Priority: 10, Exclusive: true},
+				{Name: "early", Generator: stub("early", "_early"), Priority: 20},
+			},
+			want: []string{"_early early", "_type type", "_late late"},
+		},
+		{
+			name: "repeated IDs keep the first case",
+			registrations: []Registration{
+				{Name: "a", Generator: stub("a", "_x", "_y"), Priority: 2},
+				{Name: "b", Generator: stub("b", "_y", "_z"), Priority: 1},
+			},
+			want: []string{"_x a", "_y a", "_z b"},
+		},
+		{
+			name: "matchers select registrations",
+			registrations: []Registration{
+				{Name: "strings", Generator: stub("strings", "_s"), Match: MatchType("string"), Priority: 5, Exclusive: true},
+				{Name: "integers", Generator: stub("integers", "_i"), Match: MatchType("integer"), Priority: 1, Exclusive: true},
+			},
+			want: []string{"_i integers"},
+		},
+		{
+			name: "disabled registrations fall through",
+			registrations: []Registration{
+				{Name: "preferred", Generator: stub("preferred", "_p"), Priority: 5, Exclusive: true},
+				{Name: "fallback", Generator: stub("fallback", "_f"), Exclusive: true},
+			},
+			disable: []string{"preferred"},
+			want:    []string{"_f fallback"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Registry{disabled: map[string]bool{}}
+			for _, reg := range tt.registrations {
+				r.Register(reg)
+			}
+			for _, name := range tt.disable {
+				r.Disable(name)
+			}
+			var got []string
+			for _, tc := range r.Generate(Context{BaseID: "r", Param: processor.ParameterCase{DataType: "integer"}}) {
+				got = append(got, tc.ID[len("r"):]+" "+tc.Description)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistryEnable(t *testing.T) {
+	r := NewRegistry()
+	param := processor.ParameterCase{ParamIn: "query", DataType: "string"}
+	hasSecurity := func() bool {
+		for _, tc := range r.Generate(Context{BaseID: "r", Param: param}) {
+			if tc.Type == "security" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if hasSecurity() {
+		t.Error("security cases generated before Enable")
+	}
+	r.Enable(BuiltinSecurity)
+	if !hasSecurity() {
+		t.Error("no security cases after Enable")
+	}
+	r.Disable(BuiltinSecurity)
+	if hasSecurity() {
+		t.Error("security cases generated after Disable")
+	}
+}
+
+func TestBuiltinSelection(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  string // ID suffix of a case only the expected generator produces
+		not   string // ID suffix of a case the generator it takes precedence over produces
+	}{
+		{name: "enum over integer", param: processor.ParameterCase{DataType: "integer", EnumValues: []interface{}{float64(1)}}, want: "_valid_1", not: "_boundary_min"},
+		{name: "file over array", param: processor.ParameterCase{ParamIn: "body", MediaType: "multipart/form-data", DataType: "array[file]"}, want: "_wrong_mime_type", not: "_empty_array"},
+		{name: "array", param: processor.ParameterCase{DataType: "array[integer]"}, want: "_empty_array", not: "_empty_string"},
+		{name: "union over string fallback", param: processor.ParameterCase{DataType: "integer|boolean"}, want: "_valid_boolean", not: "_empty_string"},
+		{name: "string fallback for unknown types", param: processor.ParameterCase{DataType: "unknown"}, want: "_empty_string"},
+		{name: "location cases for headers", param: processor.ParameterCase{ParamIn: "header", DataType: "string"}, want: "_name_lowercase"},
+		{name: "no location cases for queries", param: processor.ParameterCase{ParamIn: "query", DataType: "string"}, want: "_missing", not: "_name_lowercase"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("r", NewRegistry().Generate(Context{BaseID: "r", Param: tt.param}))
+			if _, ok := cases[tt.want]; !ok {
+				t.Errorf("no %s case", tt.want)
+			}
+			if _, ok := cases[tt.not]; ok && tt.not != "" {
+				t.Errorf("unexpected %s case", tt.not)
+			}
+		})
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	param := processor.ParameterCase{
+		ParamIn:     "query",
+		DataType:    "array[string]",
+		Constraints: processor.Constraints{Format: "uuid"},
+		Extensions:  map[string]interface{}{"X-Country-Code": true},
+	}
+	tests := []struct {
+		name  string
+		match Matcher
+		want  bool
+	}{
+		{name: "array type", match: MatchType("array"), want: true},
+		{name: "exact array type", match: MatchType("array[string]"), want: true},
+		{name: "other type", match: MatchType("string"), want: false},
+		{name: "format", match: MatchFormat("date", "uuid"), want: true},
+		{name: "other format", match: MatchFormat("date"), want: false},
+		{name: "extension ignoring case", match: MatchExtension("x-country-code"), want: true},
+		{name: "missing extension", match: MatchExtension("x-other"), want: false},
+		{name: "location", match: MatchLocation("path", "query"), want: true},
+		{name: "enum", match: MatchEnum(), want: false},
+		{name: "union", match: MatchUnion(), want: false},
+		{name: "all matching", match: MatchAll(MatchType("array"), MatchLocation("query")), want: true},
+		{name: "all with one failing", match: MatchAll(MatchType("array"), MatchLocation("path")), want: false},
+		{name: "all of none", match: MatchAll(), want: true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.match(param); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
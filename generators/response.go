@@ -0,0 +1,55 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// GenerateResponseTestCases generates one endpoint-level case per documented
+// response, named after its status code. Success and redirect responses are
+// reached by accepted requests and error responses by rejected ones; a
+// default response counts as an error when the operation documents any
+// success code.
+func GenerateResponseTestCases(baseID string, ep processor.EndpointCases) []TestCase {
+	hasSuccess := false
+	for _, resp := range ep.Responses {
+		if strings.HasPrefix(resp.StatusCode, "2") {
+			hasSuccess = true
+		}
+	}
+
+	var testCases []TestCase
+	for _, resp := range ep.Responses {
+		accepted := strings.HasPrefix(resp.StatusCode, "2") || strings.HasPrefix(resp.StatusCode, "3")
+		if strings.EqualFold(resp.StatusCode, "default") {
+			accepted = !hasSuccess
+		}
+
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_response_" + strings.ToLower(resp.StatusCode),
+			Type:        "response",
+			Expected:    expectation(accepted),
+			Description: responseDescription(ep, resp),
+		})
+	}
+	return testCases
+}
+
+// responseDescription names the response path of an endpoint, e.g.
+// "404 response of GET /pet/{petId}: Pet not found", followed by the required
+// fields and headers to check
+func responseDescription(ep processor.EndpointCases, resp processor.ResponseCase) string {
+	desc := fmt.Sprintf("%s response of %s %s", resp.StatusCode, strings.ToUpper(ep.Method), ep.Endpoint)
+	if resp.Description != "" {
+		desc += ": " + resp.Description
+	}
+	if len(resp.RequiredFields) > 0 {
+		desc += " (required fields: " + strings.Join(resp.RequiredFields, ", ") + ")"
+	}
+	if len(resp.Headers) > 0 {
+		desc += " (headers: " + strings.Join(resp.Headers, ", ") + ")"
+	}
+	return desc
+}
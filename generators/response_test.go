@@ -0,0 +1,77 @@
+package generators
+
+import (
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestResponseCases(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []processor.ResponseCase
+		want      map[string]string // case ID suffix to expected outcome
+	}{
+		{
+			name: "success, redirect and errors",
+			responses: []processor.ResponseCase{
+				{StatusCode: "200"}, {StatusCode: "302"}, {StatusCode: "404"}, {StatusCode: "4XX"}, {StatusCode: "default"},
+			},
+			want: map[string]string{
+				"_response_200":     ExpectAccept,
+				"_response_302":     ExpectAccept,
+				"_response_404":     ExpectReject,
+				"_response_4xx":     ExpectReject,
+				"_response_default": ExpectReject,
+			},
+		},
+		{
+			name:      "default as the only success",
+			responses: []processor.ResponseCase{{StatusCode: "400"}, {StatusCode: "DEFAULT"}},
+			want:      map[string]string{"_response_400": ExpectReject, "_response_default": ExpectAccept},
+		},
+		{
+			name:      "success range",
+			responses: []processor.ResponseCase{{StatusCode: "2XX"}, {StatusCode: "default"}},
+			want:      map[string]string{"_response_2xx": ExpectAccept, "_response_default": ExpectReject},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := processor.EndpointCases{Endpoint: "/pets", Method: "get", Responses: tt.responses}
+			cases := casesByID("r", GenerateResponseTestCases("r", ep))
+			if len(cases) != len(tt.want) {
+				t.Errorf("got %d cases, want %d", len(cases), len(tt.want))
+			}
+			for id, want := range tt.want {
+				if tc, ok := cases[id]; !ok || tc.Expected != want || tc.Type != "response" {
+					t.Errorf("%s: got %q %q, want response %q", id, tc.Type, tc.Expected, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseDescription(t *testing.T) {
+	ep := processor.EndpointCases{Endpoint: "/pet/{petId}", Method: "get"}
+	tests := []struct {
+		resp processor.ResponseCase
+		want string
+	}{
+		{
+			resp: processor.ResponseCase{StatusCode: "404", Description: "Pet not found"},
+			want: "404 response of GET /pet/{petId}: Pet not found",
+		},
+		{
+			resp: processor.ResponseCase{StatusCode: "200", RequiredFields: []string{"id", "owner.name"}, Headers: []string{"X-Rate-Limit"}},
+			want: "200 response of GET /pet/{petId} (required fields: id, owner.name) (headers: X-Rate-Limit)",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := responseDescription(ep, tt.resp); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
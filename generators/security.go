@@ -0,0 +1,100 @@
+package generators
+
+import (
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// oversizedPayloadLength is the length of the oversized payload case, 1 MiB
+const oversizedPayloadLength = 1 << 20
+
+// SecurityGenerator handles security-oriented test cases: injection payloads,
+// path traversal, header injection, SSRF targets, oversized payloads and
+// unicode normalization tricks. It is opt-in, see Enable(BuiltinSecurity).
+//
+// A payload the schema accepts (free text) is expected to be accepted and
+// handled as plain data, any other payload to be rejected.
+type SecurityGenerator struct{}
+
+// securityPayload is a single security test case
+type securityPayload struct {
+	name        string
+	description string
+	value       interface{}
+}
+
+// GenerateTestCases generates security test cases for primitive parameters and body fields
+func (g *SecurityGenerator) GenerateTestCases(ctx Context) []TestCase {
+	param := ctx.Param
+	if strings.HasPrefix(param.DataType, "array") || param.DataType == "object" || param.DataType == "file" {
+		return nil
+	}
+
+	payloads := []securityPayload{
+		{"sql_injection", "SQL injection", "' OR '1'='1' --"},
+		{"nosql_injection", "NoSQL operator injection", nosqlPayload(param)},
+		{"xss", "Cross-site scripting", "<script>alert(1)</script>"},
+		{"unicode_normalization", "Fullwidth characters normalizing to a script tag", "＜script＞alert(1)＜／script＞"},
+	}
+
+	switch param.ParamIn {
+	case "path":
+		payloads = append(payloads, securityPayload{"path_traversal", "Encoded path traversal", "..%2F..%2F..%2Fetc%2Fpasswd"})
+	case "header":
+		payloads = append(payloads, securityPayload{"header_injection", "CRLF header injection", "value\r\nX-Injected: true"})
+	}
+
+	if f := param.Constraints.Format; f == "uri" || f == "url" {
+		payloads = append(payloads,
+			securityPayload{"ssrf_metadata", "SSRF to a cloud metadata endpoint", "http://169.254.169.254/latest/meta-data/"},
+			securityPayload{"ssrf_localhost", "SSRF to a local service", "http://localhost:22/"},
+			securityPayload{"ssrf_file_scheme", "SSRF through the file scheme", "file:///etc/passwd"},
+		)
+	}
+
+	var testCases []TestCase
+	for _, p := range payloads {
+		expected := securityExpectation(param, p.value)
+		// SSRF targets are valid URIs the server must still refuse to reach
+		if strings.HasPrefix(p.name, "ssrf_") {
+			expected = ExpectReject
+		}
+		testCases = append(testCases, TestCase{
+			ID:          ctx.BaseID + "_security_" + p.name,
+			Type:        "security",
+			Expected:    expected,
+			Description: p.description,
+			Value:       p.value,
+		})
+	}
+
+	// Spelled out as the character and its count to keep the output readable
+	oversized := TestCase{
+		ID:          ctx.BaseID + "_security_oversized",
+		Type:        "security",
+		Expected:    ExpectReject,
+		Description: "Oversized payload of 1 MiB",
+		Value:       map[string]interface{}{"repeat": "A", "count": oversizedPayloadLength},
+	}
+	return append(testCases, oversized)
+}
+
+// nosqlPayload returns a query operator object for JSON bodies and its textual
+// form for the other locations
+func nosqlPayload(param processor.ParameterCase) interface{} {
+	if param.ParamIn == "body" && strings.Contains(param.MediaType, "json") {
+		return map[string]interface{}{"$ne": nil}
+	}
+	return `{"$ne": null}`
+}
+
+// securityExpectation accepts a text payload for parameters taking free text,
+// which must then treat it as data, and rejects it anywhere else
+func securityExpectation(param processor.ParameterCase, value interface{}) string {
+	text, ok := value.(string)
+	if !ok || strings.ContainsAny(text, "\r\n") {
+		return ExpectReject
+	}
+	return expectation(acceptsString(param.DataType, param.EnumValues, &param.Constraints, text))
+}
@@ -0,0 +1,109 @@
+package generators
+
+import (
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestSecurityCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]string // case ID suffix to expected outcome, "" when the case is left out
+	}{
+		{
+			name:  "free text query",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "string"},
+			want: map[string]string{
+				"_security_sql_injection":         ExpectAccept,
+				"_security_nosql_injection":       ExpectAccept,
+				"_security_xss":                   ExpectAccept,
+				"_security_unicode_normalization": ExpectAccept,
+				"_security_oversized":             ExpectReject,
+				"_security_path_traversal":        "",
+				"_security_header_injection":      "",
+				"_security_ssrf_metadata":         "",
+			},
+		},
+		{
+			name:  "free text json body field",
+			param: processor.ParameterCase{ParamIn: "body", MediaType: "application/json", DataType: "string"},
+			want: map[string]string{
+				"_security_sql_injection":   ExpectAccept,
+				"_security_nosql_injection": ExpectReject,
+			},
+		},
+		{
+			name:  "integer query",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "integer"},
+			want: map[string]string{
+				"_security_sql_injection": ExpectReject,
+				"_security_xss":           ExpectReject,
+			},
+		},
+		{
+			name:  "patterned string",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "string", Constraints: processor.Constraints{Pattern: "^[a-z]+$"}},
+			want:  map[string]string{"_security_sql_injection": ExpectReject},
+		},
+		{
+			name:  "enum",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "string", EnumValues: []interface{}{"a"}},
+			want:  map[string]string{"_security_xss": ExpectReject},
+		},
+		{
+			name:  "path segment",
+			param: processor.ParameterCase{ParamIn: "path", DataType: "string"},
+			want:  map[string]string{"_security_path_traversal": ExpectAccept, "_security_header_injection": ""},
+		},
+		{
+			name:  "header",
+			param: processor.ParameterCase{ParamIn: "header", DataType: "string"},
+			want:  map[string]string{"_security_header_injection": ExpectReject, "_security_path_traversal": ""},
+		},
+		{
+			name:  "uri format",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "string", Constraints: processor.Constraints{Format: "uri"}},
+			want: map[string]string{
+				"_security_ssrf_metadata":    ExpectReject,
+				"_security_ssrf_localhost":   ExpectReject,
+				"_security_ssrf_file_scheme": ExpectReject,
+				"_security_sql_injection":    ExpectReject,
+			},
+		},
+		{
+			name:  "array",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "array[string]"},
+			want:  map[string]string{"_security_sql_injection": "", "_security_oversized": ""},
+		},
+		{
+			name:  "object",
+			param: processor.ParameterCase{ParamIn: "body", MediaType: "application/json", DataType: "object"},
+			want:  map[string]string{"_security_sql_injection": "", "_security_oversized": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("s", (&SecurityGenerator{}).GenerateTestCases(Context{BaseID: "s", Param: tt.param}))
+			for id, tc := range cases {
+				if tc.Type != "security" {
+					t.Errorf("%s: type %q, want security", id, tc.Type)
+				}
+			}
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == "" {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				if !ok || tc.Expected != want {
+					t.Errorf("%s: got %q, want %q", id, tc.Expected, want)
+				}
+			}
+		})
+	}
+}
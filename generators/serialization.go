@@ -0,0 +1,351 @@
+package generators
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// SerializationGenerator handles how array and object parameters are written
+// into the URL, a header or a form: the declared OpenAPI 3 style and explode
+// or Swagger 2.0 collectionFormat against the other common serializations,
+// and reserved characters in query values. Values are the parameter as it
+// appears on the wire, so the cases have their own serialization type.
+type SerializationGenerator struct{}
+
+// arraySerialization is one way of writing an array parameter
+type arraySerialization struct {
+	name    string // ID fragment and description
+	style   string // OpenAPI 3 style, tabDelimited standing for Swagger 2.0 tsv
+	explode bool
+}
+
+// Serializations tried for array parameters, by location
+var arraySerializations = map[string][]arraySerialization{
+	"query": {
+		{"repeated_keys", "form", true},
+		{"comma_list", "form", false},
+		{"space_delimited", "spaceDelimited", false},
+		{"pipe_delimited", "pipeDelimited", false},
+	},
+	"path": {
+		{"comma_list", "simple", false},
+		{"label", "label", false},
+		{"matrix", "matrix", false},
+		{"matrix_exploded", "matrix", true},
+	},
+	"header": {
+		{"comma_list", "simple", false},
+	},
+	"cookie": {
+		{"comma_list", "form", false},
+		{"repeated_keys", "form", true},
+	},
+}
+
+// reservedSample holds reserved characters that do not end a query value:
+// RFC 3986 allows / : @ unencoded in a query, so both forms read the same
+const reservedSample = "a/b:c@d"
+
+// reservedDelimiterSample holds reserved characters a query value only holds
+// unencoded when the parameter sets allowReserved
+const reservedDelimiterSample = "a[0]!$'()*,;b"
+
+// GenerateTestCases generates serialization test cases for parameters sent
+// outside the request body
+func (g *SerializationGenerator) GenerateTestCases(ctx Context) []TestCase {
+	param := ctx.Param
+	switch {
+	case strings.HasPrefix(param.DataType, "array"):
+		return arraySerializationCases(ctx)
+	case param.DataType == "object":
+		return objectSerializationCases(ctx)
+	case param.DataType == "string" && param.ParamIn == "query" && param.Style != "":
+		return reservedCases(ctx)
+	}
+	return nil
+}
+
+// arraySerializationCases sends the same items serialized the declared way,
+// which is accepted, and the other ways. A server reading a wrongly
+// serialized list sees a single item holding the whole list, accepted only
+// when such an item is valid, or a repeated parameter, which is rejected.
+func arraySerializationCases(ctx Context) []TestCase {
+	param := ctx.Param
+	declared, ok := declaredArraySerialization(param)
+	if !ok {
+		return nil
+	}
+
+	c := param.Constraints
+	n := int64(2)
+	if c.MinItems != nil && *c.MinItems > n {
+		n = *c.MinItems
+	}
+	// Serializations only differ for several items
+	if n > maxSampleItems || (c.MaxItems != nil && *c.MaxItems < n) {
+		return nil
+	}
+	itemType := arrayItemType(param.DataType)
+	values := arrayValues(itemType, c.Items, n)
+	// Swagger 2.0 array parameters carry the enum of their items
+	if len(param.EnumValues) > 0 {
+		for i := range values {
+			values[i] = param.EnumValues[i%len(param.EnumValues)]
+		}
+	}
+	var items []string
+	for _, v := range values {
+		items = append(items, fmt.Sprint(v))
+	}
+
+	location := param.ParamIn
+	if location == "formData" {
+		location = "query"
+	}
+	candidates := append([]arraySerialization{}, arraySerializations[location]...)
+	if param.CollectionFormat != "" && location == "query" {
+		candidates = append(candidates, arraySerialization{"tab_delimited", "tabDelimited", false})
+	}
+
+	wire := serializeArray(param.ParamName, location, items, declared.style, declared.explode)
+	var testCases []TestCase
+	for _, s := range candidates {
+		value := serializeArray(param.ParamName, location, items, s.style, s.explode)
+		label := strings.ReplaceAll(s.name, "_", " ")
+		tc := TestCase{
+			ID:          ctx.BaseID + "_serialized_" + s.name,
+			Type:        "serialization",
+			Expected:    ExpectAccept,
+			Description: fmt.Sprintf("Items sent as %s, the declared serialization", label),
+			Value:       value,
+		}
+		if value != wire {
+			tc.Expected, tc.Description = misreadArray(param, location, value, itemType, items, s, declared)
+		}
+		testCases = append(testCases, tc)
+	}
+
+	return testCases
+}
+
+// declaredArraySerialization returns the serialization the specification
+// declares for an array parameter
+func declaredArraySerialization(param processor.ParameterCase) (arraySerialization, bool) {
+	query := param.ParamIn == "query" || param.ParamIn == "formData" || param.ParamIn == "cookie"
+	switch param.CollectionFormat {
+	case "":
+		if param.Style == "" {
+			return arraySerialization{}, false
+		}
+		return arraySerialization{style: param.Style, explode: param.Explode}, true
+	case "multi":
+		return arraySerialization{style: "form", explode: true}, true
+	case "ssv":
+		return arraySerialization{style: "spaceDelimited"}, true
+	case "tsv":
+		return arraySerialization{style: "tabDelimited"}, true
+	case "pipes":
+		return arraySerialization{style: "pipeDelimited"}, true
+	}
+	if query {
+		return arraySerialization{style: "form"}, true
+	}
+	return arraySerialization{style: "simple"}, true
+}
+
+// misreadArray gives the expectation and description of an array sent with
+// a serialization other than the declared one, given as value on the wire
+func misreadArray(param processor.ParameterCase, location, value, itemType string, items []string, sent, declared arraySerialization) (string, string) {
+	label := strings.ReplaceAll(sent.name, "_", " ")
+	if sent.explode && !declared.explode && sent.style != "matrix" {
+		return ExpectReject, fmt.Sprintf("Items sent as %s instead of the declared %s serialization (rejected)", label, declared.style)
+	}
+
+	// The server reads the whole list as one item
+	item := strings.Join(items, arrayDelimiters[sent.style])
+	if location == "path" || location == "header" {
+		item, _ = url.PathUnescape(value)
+	}
+	c := param.Constraints
+	accepted := acceptsString(itemType, param.EnumValues, c.Items, item) && (c.MinItems == nil || *c.MinItems <= 1)
+	return expectation(accepted), fmt.Sprintf("Items sent as %s instead of the declared %s serialization, read as the single item %q", label, declared.style, item)
+}
+
+// Item separators of the non-exploded query serializations
+var arrayDelimiters = map[string]string{
+	"form":           ",",
+	"spaceDelimited": " ",
+	"pipeDelimited":  "|",
+	"tabDelimited":   "\t",
+}
+
+// serializeArray writes array items as they appear in a query string, path
+// segment, header value or cookie
+func serializeArray(name, location string, items []string, style string, explode bool) string {
+	escape := url.QueryEscape
+	switch location {
+	case "path":
+		escape = url.PathEscape
+	case "header":
+		escape = func(s string) string { return s }
+	}
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = escape(item)
+	}
+
+	switch {
+	case style == "label":
+		return "." + strings.Join(escaped, ".")
+	case style == "matrix" && explode:
+		return ";" + name + "=" + strings.Join(escaped, ";"+name+"=")
+	case style == "matrix":
+		return ";" + name + "=" + strings.Join(escaped, ",")
+	case style == "simple":
+		return strings.Join(escaped, ",")
+	case explode:
+		key := url.QueryEscape(name)
+		return key + "=" + strings.Join(escaped, "&"+key+"=")
+	}
+	return url.QueryEscape(name) + "=" + strings.Join(escaped, queryDelimiters[style])
+}
+
+// Item separators as written in a query string or cookie
+var queryDelimiters = map[string]string{
+	"form":           ",",
+	"spaceDelimited": "%20",
+	"pipeDelimited":  "|",
+	"tabDelimited":   "%09",
+}
+
+// objectSerializationCases sends the same object as form fields, a comma
+// list and deepObject brackets in the query, or as the two simple forms in a
+// path or header. Only the declared serialization is expected to be accepted.
+func objectSerializationCases(ctx Context) []TestCase {
+	param := ctx.Param
+	if param.Style == "" {
+		return nil
+	}
+
+	object := sampleObject(param.Constraints)
+	if len(object) == 0 {
+		for name, dataType := range param.Constraints.Properties {
+			object[name] = sampleValue(dataType, nil)
+		}
+	}
+	if len(object) == 0 {
+		object["key"] = "value"
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	name := url.QueryEscape(param.ParamName)
+	var pairs, list, deep []string
+	for _, key := range keys {
+		k, v := url.QueryEscape(key), url.QueryEscape(fmt.Sprint(object[key]))
+		pairs = append(pairs, k+"="+v)
+		list = append(list, k, v)
+		deep = append(deep, name+"["+k+"]="+v)
+	}
+
+	type serialization struct {
+		name    string
+		style   string
+		explode bool
+		value   string
+	}
+	var candidates []serialization
+	switch param.ParamIn {
+	case "query":
+		candidates = []serialization{
+			{"form_exploded", "form", true, strings.Join(pairs, "&")},
+			{"comma_list", "form", false, name + "=" + strings.Join(list, ",")},
+			{"deep_object", "deepObject", true, strings.Join(deep, "&")},
+		}
+	case "path", "header":
+		candidates = []serialization{
+			{"comma_list", "simple", false, strings.Join(list, ",")},
+			{"key_value_list", "simple", true, strings.Join(pairs, ",")},
+		}
+	}
+
+	var testCases []TestCase
+	for _, s := range candidates {
+		label := strings.ReplaceAll(s.name, "_", " ")
+		tc := TestCase{
+			ID:          ctx.BaseID + "_serialized_" + s.name,
+			Type:        "serialization",
+			Expected:    ExpectAccept,
+			Description: fmt.Sprintf("Properties sent as %s, the declared serialization", label),
+			Value:       s.value,
+		}
+		// deepObject is only defined exploded
+		if s.style != param.Style || (s.explode != param.Explode && s.style != "deepObject") {
+			tc.Expected = ExpectReject
+			tc.Description = fmt.Sprintf("Properties sent as %s instead of the declared %s serialization (rejected)", label, param.Style)
+		}
+		testCases = append(testCases, tc)
+	}
+	return testCases
+}
+
+// reservedCases sends reserved characters in a query string value, encoded
+// and as is. The query-safe / : @ decode to the same value either way, so
+// both are accepted whether or not allowReserved is set. The delimiters
+// [ ] ! $ ' ( ) * , ; must be percent-encoded unless allowReserved is set.
+func reservedCases(ctx Context) []TestCase {
+	param := ctx.Param
+	name := url.QueryEscape(param.ParamName)
+
+	var testCases []TestCase
+	if acceptsString(param.DataType, param.EnumValues, &param.Constraints, reservedSample) {
+		testCases = append(testCases,
+			TestCase{
+				ID:          ctx.BaseID + "_reserved_encoded",
+				Type:        "serialization",
+				Expected:    ExpectAccept,
+				Description: "Reserved characters / : @ sent percent-encoded",
+				Value:       name + "=" + url.QueryEscape(reservedSample),
+			},
+			TestCase{
+				ID:          ctx.BaseID + "_reserved_unencoded",
+				Type:        "serialization",
+				Expected:    ExpectAccept,
+				Description: "Reserved characters / : @ sent unencoded, which a query string allows",
+				Value:       name + "=" + reservedSample,
+			},
+		)
+	}
+
+	if acceptsString(param.DataType, param.EnumValues, &param.Constraints, reservedDelimiterSample) {
+		unencoded := TestCase{
+			ID:          ctx.BaseID + "_reserved_delimiters_unencoded",
+			Type:        "serialization",
+			Expected:    ExpectAccept,
+			Description: "Reserved characters [ ] ! $ ' ( ) * , ; sent unencoded (allowReserved)",
+			Value:       name + "=" + reservedDelimiterSample,
+		}
+		if !param.AllowReserved {
+			unencoded.Expected = ExpectReject
+			unencoded.Description = "Reserved characters [ ] ! $ ' ( ) * , ; sent unencoded (rejected, allowReserved is not set)"
+		}
+		testCases = append(testCases,
+			TestCase{
+				ID:          ctx.BaseID + "_reserved_delimiters_encoded",
+				Type:        "serialization",
+				Expected:    ExpectAccept,
+				Description: "Reserved characters [ ] ! $ ' ( ) * , ; sent percent-encoded",
+				Value:       name + "=" + url.QueryEscape(reservedDelimiterSample),
+			},
+			unencoded,
+		)
+	}
+	return testCases
+}
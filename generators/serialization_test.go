@@ -0,0 +1,300 @@
+package generators
+
+import (
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestReservedCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]string // case ID suffix to expected outcome, "" when the case is left out
+	}{
+		{
+			name:  "without allowReserved",
+			param: processor.ParameterCase{ParamName: "q", ParamIn: "query", DataType: "string", Style: "form", Explode: true},
+			want: map[string]string{
+				"_reserved_encoded":              ExpectAccept,
+				"_reserved_unencoded":            ExpectAccept,
+				"_reserved_delimiters_encoded":   ExpectAccept,
+				"_reserved_delimiters_unencoded": ExpectReject,
+			},
+		},
+		{
+			name:  "with allowReserved",
+			param: processor.ParameterCase{ParamName: "q", ParamIn: "query", DataType: "string", Style: "form", Explode: true, AllowReserved: true},
+			want: map[string]string{
+				"_reserved_delimiters_encoded":   ExpectAccept,
+				"_reserved_delimiters_unencoded": ExpectAccept,
+			},
+		},
+		{
+			name: "values the constraints rule out",
+			param: processor.ParameterCase{ParamName: "q", ParamIn: "query", DataType: "string", Style: "form", Explode: true,
+				Constraints: processor.Constraints{Pattern: "^[a-z/:@]+$"}},
+			want: map[string]string{
+				"_reserved_encoded":              ExpectAccept,
+				"_reserved_delimiters_encoded":   "",
+				"_reserved_delimiters_unencoded": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("p", (&SerializationGenerator{}).GenerateTestCases(Context{BaseID: "p", Param: tt.param}))
+			for id, want := range tt.want {
+				if got := cases[id].Expected; got != want {
+					t.Errorf("%s: expected %q, want %q", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDeclaredArraySerialization(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  arraySerialization
+		ok    bool
+	}{
+		{"no style or collectionFormat", processor.ParameterCase{ParamIn: "query"}, arraySerialization{}, false},
+		{"OpenAPI 3 style", processor.ParameterCase{ParamIn: "query", Style: "pipeDelimited"}, arraySerialization{style: "pipeDelimited"}, true},
+		{"OpenAPI 3 exploded style", processor.ParameterCase{ParamIn: "query", Style: "form", Explode: true}, arraySerialization{style: "form", explode: true}, true},
+		{"multi", processor.ParameterCase{ParamIn: "query", CollectionFormat: "multi"}, arraySerialization{style: "form", explode: true}, true},
+		{"ssv", processor.ParameterCase{ParamIn: "query", CollectionFormat: "ssv"}, arraySerialization{style: "spaceDelimited"}, true},
+		{"tsv", processor.ParameterCase{ParamIn: "query", CollectionFormat: "tsv"}, arraySerialization{style: "tabDelimited"}, true},
+		{"pipes", processor.ParameterCase{ParamIn: "query", CollectionFormat: "pipes"}, arraySerialization{style: "pipeDelimited"}, true},
+		{"csv in a query", processor.ParameterCase{ParamIn: "query", CollectionFormat: "csv"}, arraySerialization{style: "form"}, true},
+		{"csv in a form", processor.ParameterCase{ParamIn: "formData", CollectionFormat: "csv"}, arraySerialization{style: "form"}, true},
+		{"csv in a path", processor.ParameterCase{ParamIn: "path", CollectionFormat: "csv"}, arraySerialization{style: "simple"}, true},
+		{"csv in a header", processor.ParameterCase{ParamIn: "header", CollectionFormat: "csv"}, arraySerialization{style: "simple"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := declaredArraySerialization(tt.param)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("declaredArraySerialization() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSerializeArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		items    []string
+		style    string
+		explode  bool
+		want     string
+	}{
+		{"form exploded", "query", []string{"a", "b"}, "form", true, "ids=a&ids=b"},
+		{"form", "query", []string{"a", "b"}, "form", false, "ids=a,b"},
+		{"space delimited", "query", []string{"a", "b"}, "spaceDelimited", false, "ids=a%20b"},
+		{"pipe delimited", "query", []string{"a", "b"}, "pipeDelimited", false, "ids=a|b"},
+		{"tab delimited", "query", []string{"a", "b"}, "tabDelimited", false, "ids=a%09b"},
+		{"query items escaped", "query", []string{"a b", "c&d"}, "form", false, "ids=a+b,c%26d"},
+		{"simple", "path", []string{"a", "b"}, "simple", false, "a,b"},
+		{"path items escaped", "path", []string{"a b", "c"}, "simple", false, "a%20b,c"},
+		{"label", "path", []string{"a", "b"}, "label", false, ".a.b"},
+		{"matrix", "path", []string{"a", "b"}, "matrix", false, ";ids=a,b"},
+		{"matrix exploded", "path", []string{"a", "b"}, "matrix", true, ";ids=a;ids=b"},
+		{"header items as is", "header", []string{"a b", "c"}, "simple", false, "a b,c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serializeArray("ids", tt.location, tt.items, tt.style, tt.explode); got != tt.want {
+				t.Errorf("serializeArray() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArraySerializationCases(t *testing.T) {
+	minTwo := int64(2)
+	maxOne := int64(1)
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]string // case ID suffix to expected outcome, "" when the case is left out
+	}{
+		{
+			name:  "exploded form with an enum",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "query", DataType: "array[string]", Style: "form", Explode: true, EnumValues: []interface{}{"a", "b"}},
+			want: map[string]string{
+				"_serialized_repeated_keys":   ExpectAccept,
+				"_serialized_comma_list":      ExpectReject,
+				"_serialized_space_delimited": ExpectReject,
+				"_serialized_pipe_delimited":  ExpectReject,
+				"_serialized_tab_delimited":   "",
+			},
+		},
+		{
+			name:  "whole list read as a valid string item",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "query", DataType: "array[string]", Style: "form", Explode: true},
+			want: map[string]string{
+				"_serialized_repeated_keys": ExpectAccept,
+				"_serialized_comma_list":    ExpectAccept,
+			},
+		},
+		{
+			name:  "a single item is too few",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "query", DataType: "array[string]", Style: "form", Explode: true, Constraints: processor.Constraints{MinItems: &minTwo}},
+			want: map[string]string{
+				"_serialized_repeated_keys": ExpectAccept,
+				"_serialized_comma_list":    ExpectReject,
+			},
+		},
+		{
+			name:  "whole list read as an integer item",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "query", DataType: "array[integer]", Style: "form"},
+			want: map[string]string{
+				"_serialized_comma_list":      ExpectAccept,
+				"_serialized_repeated_keys":   ExpectReject,
+				"_serialized_space_delimited": ExpectReject,
+			},
+		},
+		{
+			name:  "Swagger 2.0 collectionFormat adds tsv",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "query", DataType: "array[integer]", CollectionFormat: "tsv"},
+			want: map[string]string{
+				"_serialized_tab_delimited":  ExpectAccept,
+				"_serialized_comma_list":     ExpectReject,
+				"_serialized_pipe_delimited": ExpectReject,
+			},
+		},
+		{
+			name:  "formData reads like a query",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "formData", DataType: "array[integer]", CollectionFormat: "multi"},
+			want: map[string]string{
+				"_serialized_repeated_keys": ExpectAccept,
+				"_serialized_comma_list":    ExpectReject,
+			},
+		},
+		{
+			name:  "path",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "path", DataType: "array[integer]", Style: "simple"},
+			want: map[string]string{
+				"_serialized_comma_list":      ExpectAccept,
+				"_serialized_label":           ExpectReject,
+				"_serialized_matrix":          ExpectReject,
+				"_serialized_matrix_exploded": ExpectReject,
+				"_serialized_repeated_keys":   "",
+			},
+		},
+		{
+			name:  "header",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "header", DataType: "array[integer]", Style: "simple"},
+			want: map[string]string{
+				"_serialized_comma_list": ExpectAccept,
+				"_serialized_label":      "",
+			},
+		},
+		{
+			name:  "no declared serialization",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "query", DataType: "array[integer]"},
+			want:  map[string]string{"_serialized_comma_list": ""},
+		},
+		{
+			name:  "too few items allowed to tell serializations apart",
+			param: processor.ParameterCase{ParamName: "ids", ParamIn: "query", DataType: "array[integer]", Style: "form", Constraints: processor.Constraints{MaxItems: &maxOne}},
+			want:  map[string]string{"_serialized_comma_list": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("p", (&SerializationGenerator{}).GenerateTestCases(Context{BaseID: "p", Param: tt.param}))
+			for id, want := range tt.want {
+				if got := cases[id].Expected; got != want {
+					t.Errorf("%s: expected %q, want %q", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestObjectSerializationCases(t *testing.T) {
+	type outcome struct {
+		value    interface{}
+		expected string
+	}
+	constraints := processor.Constraints{Required: []string{"a", "b"}, Properties: map[string]string{"a": "integer", "b": "integer"}}
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]outcome // case ID suffix to value and expected outcome
+	}{
+		{
+			name:  "exploded form",
+			param: processor.ParameterCase{ParamName: "f", ParamIn: "query", DataType: "object", Style: "form", Explode: true, Constraints: constraints},
+			want: map[string]outcome{
+				"_serialized_form_exploded": {"a=1&b=1", ExpectAccept},
+				"_serialized_comma_list":    {"f=a,1,b,1", ExpectReject},
+				"_serialized_deep_object":   {"f[a]=1&f[b]=1", ExpectReject},
+			},
+		},
+		{
+			name:  "form",
+			param: processor.ParameterCase{ParamName: "f", ParamIn: "query", DataType: "object", Style: "form", Constraints: constraints},
+			want: map[string]outcome{
+				"_serialized_form_exploded": {"a=1&b=1", ExpectReject},
+				"_serialized_comma_list":    {"f=a,1,b,1", ExpectAccept},
+			},
+		},
+		{
+			name:  "deepObject whatever explode says",
+			param: processor.ParameterCase{ParamName: "f", ParamIn: "query", DataType: "object", Style: "deepObject", Constraints: constraints},
+			want: map[string]outcome{
+				"_serialized_deep_object":   {"f[a]=1&f[b]=1", ExpectAccept},
+				"_serialized_form_exploded": {"a=1&b=1", ExpectReject},
+			},
+		},
+		{
+			name:  "path",
+			param: processor.ParameterCase{ParamName: "f", ParamIn: "path", DataType: "object", Style: "simple", Constraints: constraints},
+			want: map[string]outcome{
+				"_serialized_comma_list":     {"a,1,b,1", ExpectAccept},
+				"_serialized_key_value_list": {"a=1,b=1", ExpectReject},
+			},
+		},
+		{
+			name:  "exploded header",
+			param: processor.ParameterCase{ParamName: "f", ParamIn: "header", DataType: "object", Style: "simple", Explode: true, Constraints: constraints},
+			want: map[string]outcome{
+				"_serialized_comma_list":     {"a,1,b,1", ExpectReject},
+				"_serialized_key_value_list": {"a=1,b=1", ExpectAccept},
+			},
+		},
+		{
+			name:  "no required properties",
+			param: processor.ParameterCase{ParamName: "f", ParamIn: "query", DataType: "object", Style: "form", Explode: true},
+			want: map[string]outcome{
+				"_serialized_form_exploded": {"key=value", ExpectAccept},
+			},
+		},
+		{
+			name:  "no declared style",
+			param: processor.ParameterCase{ParamName: "f", ParamIn: "query", DataType: "object", Constraints: constraints},
+			want:  map[string]outcome{"_serialized_form_exploded": {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("p", (&SerializationGenerator{}).GenerateTestCases(Context{BaseID: "p", Param: tt.param}))
+			for id, want := range tt.want {
+				tc := cases[id]
+				if tc.Value != want.value || tc.Expected != want.expected {
+					t.Errorf("%s: got %v (%s), want %v (%s)", id, tc.Value, tc.Expected, want.value, want.expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// GenerateServerTestCases generates one endpoint-level case per server an
+// endpoint is served under, for endpoints with several: multiple servers, or
+// server variables with enum values. Cases are named after the variable
+// values, e.g. _server_eu_v2, or after the server URL without variables.
+func GenerateServerTestCases(baseID string, ep processor.EndpointCases) []TestCase {
+	if len(ep.Servers) < 2 {
+		return nil
+	}
+
+	labels := make([]string, len(ep.Servers))
+	for i, server := range ep.Servers {
+		labels[i] = serverLabel(server)
+	}
+	fragments := enumIDFragments(stringsToValues(labels))
+
+	testCases := make([]TestCase, 0, len(ep.Servers))
+	for i, server := range ep.Servers {
+		desc := fmt.Sprintf("%s %s%s", strings.ToUpper(ep.Method), server.URL, ep.Endpoint)
+		if len(server.Variables) > 0 {
+			desc += " (" + serverVariables(server) + ")"
+		}
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_server_" + fragments[i],
+			Type:        "server",
+			Expected:    ExpectAccept,
+			Description: desc,
+			Value:       server.URL + ep.Endpoint,
+		})
+	}
+	return testCases
+}
+
+// serverLabel names a server by its variable values in variable name order,
+// or by its URL without the scheme
+func serverLabel(server processor.Server) string {
+	if len(server.Variables) == 0 {
+		label := server.URL
+		if i := strings.Index(label, "://"); i >= 0 {
+			label = label[i+3:]
+		}
+		return label
+	}
+
+	names := sortedVariableNames(server)
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = server.Variables[name]
+	}
+	return strings.Join(values, "_")
+}
+
+// serverVariables lists the variables of a server as name=value pairs
+func serverVariables(server processor.Server) string {
+	names := sortedVariableNames(server)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + server.Variables[name]
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func sortedVariableNames(server processor.Server) []string {
+	names := make([]string, 0, len(server.Variables))
+	for name := range server.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
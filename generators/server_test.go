@@ -0,0 +1,81 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestServerCases(t *testing.T) {
+	tests := []struct {
+		name    string
+		servers []processor.Server
+		want    map[string]string // case ID suffix to the URL requested
+	}{
+		{name: "no server"},
+		{name: "a single server", servers: []processor.Server{{URL: "https://api.example.com"}}},
+		{
+			name:    "several servers",
+			servers: []processor.Server{{URL: "https://api.example.com/v1"}, {URL: "http://localhost:8080"}},
+			want: map[string]string{
+				"_server_api_example_com_v1": "https://api.example.com/v1/pets",
+				"_server_localhost_8080":     "http://localhost:8080/pets",
+			},
+		},
+		{
+			name: "server variables",
+			servers: []processor.Server{
+				{URL: "https://eu.example.com/v2", Variables: map[string]string{"version": "v2", "region": "eu"}},
+				{URL: "https://us.example.com/v2", Variables: map[string]string{"version": "v2", "region": "us"}},
+			},
+			want: map[string]string{
+				"_server_eu_v2": "https://eu.example.com/v2/pets",
+				"_server_us_v2": "https://us.example.com/v2/pets",
+			},
+		},
+		{
+			name:    "colliding labels",
+			servers: []processor.Server{{URL: "https://a.example.com"}, {URL: "http://a.example.com"}},
+			want: map[string]string{
+				"_server_a_example_com":   "https://a.example.com/pets",
+				"_server_a_example_com_2": "http://a.example.com/pets",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := processor.EndpointCases{Endpoint: "/pets", Method: "get", Servers: tt.servers}
+			got := map[string]string{}
+			for _, tc := range GenerateServerTestCases("p", ep) {
+				if tc.Expected != ExpectAccept {
+					t.Errorf("%s: expected %q, want %q", tc.ID, tc.Expected, ExpectAccept)
+				}
+				got[tc.ID[len("p"):]] = tc.Value.(string)
+			}
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerDescription(t *testing.T) {
+	ep := processor.EndpointCases{Endpoint: "/pets", Method: "get", Servers: []processor.Server{
+		{URL: "https://eu.example.com/v2", Variables: map[string]string{"version": "v2", "region": "eu"}},
+		{URL: "https://api.example.com"},
+	}}
+	want := []string{
+		"GET https://eu.example.com/v2/pets (region=eu, version=v2)",
+		"GET https://api.example.com/pets",
+	}
+	for i, tc := range GenerateServerTestCases("p", ep) {
+		if tc.Description != want[i] {
+			t.Errorf("description %q, want %q", tc.Description, want[i])
+		}
+	}
+}
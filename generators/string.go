@@ -1,20 +1,338 @@
 package generators
 
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"openapi-tester/spec"
+)
+
 // StringGenerator handles test case generation for string parameters
 type StringGenerator struct{}
 
-// GenerateTestCases generates test cases for string parameters
-func (g *StringGenerator) GenerateTestCases(baseID string, paramType string, enumValues []interface{}) []TestCase {
-	return []TestCase{
-		{
+// maxSampleLength is the longest string spelled out as a test case value,
+// longer strings are given as {"repeat": ..., "count": ...}
+const maxSampleLength = 256
+
+// formatCase is a sample value for a string format
+type formatCase struct {
+	name  string
+	value string
+	valid bool
+}
+
+// Sample values for the string formats with format-specific cases
+var stringFormatCases = map[string][]formatCase{
+	"date": {
+		{"valid", "2024-01-31", true},
+		{"invalid", "31/01/2024", false},
+		{"impossible_date", "2024-02-30", false},
+	},
+	"date-time": {
+		{"valid", "2024-01-31T13:45:00Z", true},
+		{"invalid", "2024-01-31 13:45", false},
+		{"missing_timezone", "2024-01-31T13:45:00", false},
+	},
+	"email": {
+		{"valid", "user@example.com", true},
+		{"invalid", "user.example.com", false},
+		{"missing_domain", "user@", false},
+	},
+	"uuid": {
+		{"valid", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"invalid", "123e4567-e89b-12d3-a456", false},
+	},
+	"uri": {
+		{"valid", "https://example.com/path?q=1", true},
+		{"invalid", "not a uri", false},
+		{"relative", "/relative/path", false},
+	},
+	"hostname": {
+		{"valid", "api.example.com", true},
+		{"invalid", "-invalid-.example.com", false},
+		{"label_too_long", strings.Repeat("a", 64) + ".example.com", false},
+	},
+	"ipv4": {
+		{"valid", "192.168.0.1", true},
+		{"invalid", "256.1.1.1", false},
+	},
+	"ipv6": {
+		{"valid", "2001:db8::1", true},
+		{"invalid", "2001:db8::g1", false},
+	},
+	"byte": {
+		{"valid", "aGVsbG8gd29ybGQ=", true},
+		{"invalid", "not base64!", false},
+	},
+	"binary": {
+		{"valid", "\x00\x01\x02\xff", true},
+	},
+}
+
+// GenerateTestCases generates test cases for string parameters: length
+// boundaries, empty strings, pattern matches and violations, and
+// format-specific values for well-known formats
+func (g *StringGenerator) GenerateTestCases(ctx Context) []TestCase {
+	baseID, constraints := ctx.BaseID, ctx.Param.Constraints
+
+	formatCases := stringFormatCases[constraints.Format]
+
+	var testCases []TestCase
+	// Without a sample meeting every constraint only a declared example or
+	// default is known to be valid
+	if valid, ok := validString(constraints, formatCases); ok || constraints.Example != nil || constraints.Default != nil {
+		testCases = append(testCases, TestCase{
 			ID:          baseID + "_valid_input",
 			Type:        "valid",
+			Expected:    ExpectAccept,
 			Description: "Valid string input",
-		},
-		{
+			Value:       specValue(constraints, valid),
+		})
+	}
+	if invalid, ok := wrongStringValue(ctx.Param, ctx.Param.DataType, nil, &constraints); ok {
+		testCases = append(testCases, TestCase{
 			ID:          baseID + "_invalid_input",
 			Type:        "invalid",
+			Expected:    ExpectReject,
 			Description: "Invalid input for string parameter",
-		},
+			Value:       invalid,
+		})
+	}
+
+	empty := TestCase{
+		ID:          baseID + "_empty_string",
+		Type:        "valid",
+		Expected:    ExpectAccept,
+		Description: "Empty string (accepted)",
+		Value:       "",
+	}
+	if reason := emptyStringRejection(ctx.Param); reason != "" {
+		empty.Type = "invalid"
+		empty.Expected = ExpectReject
+		empty.Description = "Empty string (rejected, " + reason + ")"
+	}
+	testCases = append(testCases, empty)
+
+	if min := constraints.MinLength; min != nil && *min > 0 {
+		if value, ok := boundaryString(constraints, *min); ok {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_length_min",
+				Type:        "boundary_min",
+				Expected:    ExpectAccept,
+				Description: fmt.Sprintf("String of minimum length %d (accepted)", *min),
+				Value:       value,
+			})
+		}
+		// A minLength of 1 is already covered by the empty string
+		if *min > 1 {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_length_below_min",
+				Type:        "boundary_min",
+				Expected:    ExpectReject,
+				Description: fmt.Sprintf("String of length %d, below minLength (rejected)", *min-1),
+				Value:       repeatedString("a", *min-1),
+			})
+		}
+	}
+
+	if max := constraints.MaxLength; max != nil {
+		if value, ok := boundaryString(constraints, *max); ok {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_length_max",
+				Type:        "boundary_max",
+				Expected:    ExpectAccept,
+				Description: fmt.Sprintf("String of maximum length %d (accepted)", *max),
+				Value:       value,
+			})
+		}
+		// No string is longer than the largest int64
+		if *max < math.MaxInt64 {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_length_above_max",
+				Type:        "boundary_max",
+				Expected:    ExpectReject,
+				Description: fmt.Sprintf("String of length %d, above maxLength (rejected)", *max+1),
+				Value:       repeatedString("a", *max+1),
+			})
+		}
+	}
+
+	if constraints.Pattern != "" {
+		if sample, ok := patternSampleWithin(constraints.Pattern, constraints.MinLength, constraints.MaxLength); ok {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_pattern_match",
+				Type:        "valid",
+				Expected:    ExpectAccept,
+				Description: "String matching pattern " + constraints.Pattern,
+				Value:       sample,
+			})
+		}
+		if violation, ok := patternViolation(constraints.Pattern); ok {
+			testCases = append(testCases, TestCase{
+				ID:          baseID + "_pattern_violation",
+				Type:        "invalid",
+				Expected:    ExpectReject,
+				Description: "String not matching pattern " + constraints.Pattern,
+				Value:       violation,
+			})
+		}
+	}
+
+	for _, fc := range formatCases {
+		// A valid value of the format that breaks the length bounds is left
+		// to the length cases
+		if fc.valid && !withinLength(constraints, fc.value) {
+			continue
+		}
+		tc := TestCase{
+			ID:          baseID + "_format_" + fc.name,
+			Type:        "valid",
+			Expected:    ExpectAccept,
+			Description: fmt.Sprintf("Valid %s value", constraints.Format),
+			Value:       fc.value,
+		}
+		if !fc.valid {
+			tc.Type = "invalid"
+			tc.Expected = ExpectReject
+			tc.Description = fmt.Sprintf("Invalid %s value (%s)", constraints.Format, strings.ReplaceAll(fc.name, "_", " "))
+		}
+		testCases = append(testCases, tc)
+	}
+
+	return testCases
+}
+
+// validString picks a value satisfying the format, pattern and length
+// constraints. ok is false when the format or pattern sample cannot meet the
+// length bounds, the value then only meets the length bounds.
+func validString(c processor.Constraints, formatCases []formatCase) (interface{}, bool) {
+	for _, fc := range formatCases {
+		if fc.valid {
+			if withinLength(c, fc.value) {
+				return fc.value, true
+			}
+			return lengthString(c), false
+		}
+	}
+	if c.Pattern != "" {
+		if sample, ok := patternSampleWithin(c.Pattern, c.MinLength, c.MaxLength); ok {
+			return sample, true
+		}
+		// Patterns Go cannot compile are not checked
+		if _, err := regexp.Compile(c.Pattern); err == nil {
+			return lengthString(c), false
+		}
+	}
+	return lengthString(c), true
+}
+
+// lengthString returns a string within the length bounds
+func lengthString(c processor.Constraints) interface{} {
+	value := "valid"
+	if c.MinLength != nil && int64(len(value)) < *c.MinLength {
+		return repeatedString("a", *c.MinLength)
+	}
+	if c.MaxLength != nil && int64(len(value)) > *c.MaxLength {
+		value = value[:*c.MaxLength]
+	}
+	return value
+}
+
+// boundaryString returns a string of n characters that also matches the
+// pattern and the format, for the accepted length boundary cases. ok is false
+// when there is none, e.g. an email of 3 characters.
+func boundaryString(c processor.Constraints, n int64) (interface{}, bool) {
+	if c.Pattern != "" {
+		// Patterns Go cannot compile are not checked
+		if _, err := regexp.Compile(c.Pattern); err == nil {
+			sample, ok := patternSampleWithin(c.Pattern, &n, &n)
+			return sample, ok && matchesFormat(c.Format, sample)
+		}
+	}
+	value := repeatedString("a", n)
+	if s, ok := value.(string); ok {
+		return s, matchesFormat(c.Format, s)
+	}
+	// Long strings of a letter only suit the formats accepting any text
+	return value, matchesFormat(c.Format, "a")
+}
+
+// withinLength reports whether the length of a value in characters lies
+// within minLength and maxLength
+func withinLength(c processor.Constraints, value string) bool {
+	n := int64(utf8.RuneCountInString(value))
+	return (c.MinLength == nil || n >= *c.MinLength) && (c.MaxLength == nil || n <= *c.MaxLength)
+}
+
+// repeatedString returns s repeated n times, spelled out up to
+// maxSampleLength and as {"repeat": s, "count": n} beyond
+func repeatedString(s string, n int64) interface{} {
+	if n <= maxSampleLength {
+		return strings.Repeat(s, int(n))
+	}
+	return map[string]interface{}{"repeat": s, "count": n}
+}
+
+// emptyStringRejection explains why a parameter rejects the empty string, or
+// returns "" when it accepts it
+func emptyStringRejection(param processor.ParameterCase) string {
+	c := param.Constraints
+	switch {
+	case param.ParamIn == "path":
+		return "an empty path segment targets a different route"
+	case c.MinLength != nil && *c.MinLength > 0:
+		return "below minLength"
+	case !matchesFormat(c.Format, ""):
+		return "not a valid " + c.Format
+	case c.Pattern != "":
+		if re, err := regexp.Compile(c.Pattern); err == nil && !re.MatchString("") {
+			return "does not match the pattern"
+		}
+	}
+	return ""
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+)
+
+// matchesFormat reports whether a value is valid for a string format. Formats
+// without format-specific cases accept any value.
+func matchesFormat(format, value string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		return err == nil && addr.Address == value
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "uri":
+		u, err := url.Parse(value)
+		return err == nil && u.IsAbs() && !strings.ContainsAny(value, " \t\r\n")
+	case "hostname":
+		return len(value) <= 253 && hostnamePattern.MatchString(value)
+	case "ipv4":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+	case "ipv6":
+		return net.ParseIP(value) != nil && strings.Contains(value, ":")
+	case "byte":
+		_, err := base64.StdEncoding.DecodeString(value)
+		return err == nil
 	}
+	return true
 }
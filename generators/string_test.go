@@ -0,0 +1,237 @@
+package generators
+
+import (
+	"math"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func int64Ptr(v int64) *int64 { return &v }
+
+// casesByID indexes generated test cases by the part of their ID after the
+// base ID
+func casesByID(baseID string, testCases []TestCase) map[string]TestCase {
+	byID := map[string]TestCase{}
+	for _, tc := range testCases {
+		byID[tc.ID[len(baseID):]] = tc
+	}
+	return byID
+}
+
+func TestStringLengthCases(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints processor.Constraints
+		want        map[string]interface{} // case ID suffix to value, nil when the case is left out
+	}{
+		{
+			name:        "short bounds are spelled out",
+			constraints: processor.Constraints{MinLength: int64Ptr(3), MaxLength: int64Ptr(4)},
+			want: map[string]interface{}{
+				"_length_min":       "aaa",
+				"_length_below_min": "aa",
+				"_length_max":       "aaaa",
+				"_length_above_max": "aaaaa",
+			},
+		},
+		{
+			name:        "long bounds use the repeat descriptor",
+			constraints: processor.Constraints{MinLength: int64Ptr(1000), MaxLength: int64Ptr(2000000000)},
+			want: map[string]interface{}{
+				"_length_min":       map[string]interface{}{"repeat": "a", "count": int64(1000)},
+				"_length_max":       map[string]interface{}{"repeat": "a", "count": int64(2000000000)},
+				"_length_above_max": map[string]interface{}{"repeat": "a", "count": int64(2000000001)},
+			},
+		},
+		{
+			name:        "no case above the largest int64",
+			constraints: processor.Constraints{MaxLength: int64Ptr(math.MaxInt64)},
+			want: map[string]interface{}{
+				"_length_max":       map[string]interface{}{"repeat": "a", "count": int64(math.MaxInt64)},
+				"_length_above_max": nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("s", (&StringGenerator{}).GenerateTestCases(Context{
+				BaseID: "s",
+				Param:  processor.ParameterCase{DataType: "string", Constraints: tt.constraints},
+			}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				got := TestCase{Value: want}.FormatValue()
+				if !ok || tc.FormatValue() != got {
+					t.Errorf("%s: got %s, want %s", id, tc.FormatValue(), got)
+				}
+			}
+		})
+	}
+}
+
+func TestStringCasesMeetEveryConstraint(t *testing.T) {
+	tests := []struct {
+		name        string
+		constraints processor.Constraints
+		want        map[string]interface{} // case ID suffix to value, nil when the case is left out
+	}{
+		{
+			name:        "pattern sample grows to minLength",
+			constraints: processor.Constraints{MinLength: int64Ptr(2), MaxLength: int64Ptr(5), Pattern: "^[a-z]+$"},
+			want: map[string]interface{}{
+				"_valid_input":   "aa",
+				"_pattern_match": "aa",
+				"_length_min":    "aa",
+				"_length_max":    "aaaaa",
+			},
+		},
+		{
+			name:        "fixed-length pattern outside the length bounds",
+			constraints: processor.Constraints{MinLength: int64Ptr(4), Pattern: "^[a-z]{2}$"},
+			want: map[string]interface{}{
+				"_valid_input":   nil,
+				"_pattern_match": nil,
+				"_length_min":    nil,
+			},
+		},
+		{
+			name:        "format value longer than maxLength",
+			constraints: processor.Constraints{MaxLength: int64Ptr(5), Format: "email"},
+			want: map[string]interface{}{
+				"_valid_input":      nil,
+				"_format_valid":     nil,
+				"_length_max":       nil,
+				"_format_invalid":   "user.example.com",
+				"_length_above_max": "aaaaaa",
+			},
+		},
+		{
+			name:        "declared example is kept",
+			constraints: processor.Constraints{MaxLength: int64Ptr(5), Format: "email", Example: "a@b.c"},
+			want:        map[string]interface{}{"_valid_input": "a@b.c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("s", (&StringGenerator{}).GenerateTestCases(Context{
+				BaseID: "s",
+				Param:  processor.ParameterCase{DataType: "string", Constraints: tt.constraints},
+			}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				got := TestCase{Value: want}.FormatValue()
+				if !ok || tc.FormatValue() != got {
+					t.Errorf("%s: got %s, want %s", id, tc.FormatValue(), got)
+				}
+			}
+		})
+	}
+}
+
+func TestPatternSampleWithin(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		min, max *int64
+		want     string
+		ok       bool
+	}{
+		{pattern: "^[a-z]+$", want: "a", ok: true},
+		{pattern: "^[a-z]+$", min: int64Ptr(3), want: "aaa", ok: true},
+		{pattern: "^x[0-9]*$", min: int64Ptr(3), max: int64Ptr(3), want: "x00", ok: true},
+		{pattern: "^[a-z]{2}$", min: int64Ptr(3)},
+		{pattern: "^[a-z]{4,}$", max: int64Ptr(3)},
+		{pattern: "((a*)*)*b", min: int64Ptr(1000)},
+		{pattern: "([", want: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := patternSampleWithin(tt.pattern, tt.min, tt.max)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("patternSampleWithin(%q) = %q, %v, want %q, %v", tt.pattern, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestStringFormatCases(t *testing.T) {
+	for format, samples := range stringFormatCases {
+		t.Run(format, func(t *testing.T) {
+			cases := casesByID("s", (&StringGenerator{}).GenerateTestCases(Context{
+				BaseID: "s",
+				Param:  processor.ParameterCase{DataType: "string", Constraints: processor.Constraints{Format: format}},
+			}))
+			for _, fc := range samples {
+				if got := matchesFormat(format, fc.value); got != fc.valid {
+					t.Errorf("matchesFormat(%q) = %v, want %v", fc.value, got, fc.valid)
+				}
+				tc, ok := cases["_format_"+fc.name]
+				if !ok {
+					t.Errorf("no _format_%s case", fc.name)
+					continue
+				}
+				if want := expectation(fc.valid); tc.Expected != want || tc.Value != fc.value {
+					t.Errorf("_format_%s = %#v expected %s, want %#v expected %s", fc.name, tc.Value, tc.Expected, fc.value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPatternViolation(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+		ok      bool
+	}{
+		{pattern: "^[a-z]+$", want: "!", ok: true},
+		{pattern: "^[!a-z]*$", want: " ", ok: true},
+		{pattern: ".*", ok: false},
+		{pattern: "([", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := patternViolation(tt.pattern)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("patternViolation(%q) = %q, %v, want %q, %v", tt.pattern, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestEmptyStringRejection(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  string
+	}{
+		{name: "unconstrained", param: processor.ParameterCase{ParamIn: "query"}, want: ""},
+		{name: "path segment", param: processor.ParameterCase{ParamIn: "path"}, want: "an empty path segment targets a different route"},
+		{name: "minLength", param: processor.ParameterCase{Constraints: processor.Constraints{MinLength: int64Ptr(1)}}, want: "below minLength"},
+		{name: "minLength 0", param: processor.ParameterCase{Constraints: processor.Constraints{MinLength: int64Ptr(0)}}, want: ""},
+		{name: "format", param: processor.ParameterCase{Constraints: processor.Constraints{Format: "email"}}, want: "not a valid email"},
+		{name: "format accepting anything", param: processor.ParameterCase{Constraints: processor.Constraints{Format: "password"}}, want: ""},
+		{name: "pattern", param: processor.ParameterCase{Constraints: processor.Constraints{Pattern: "^[a-z]+$"}}, want: "does not match the pattern"},
+		{name: "pattern matching empty", param: processor.ParameterCase{Constraints: processor.Constraints{Pattern: "^[a-z]*$"}}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := emptyStringRejection(tt.param); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package generators
+
+import (
+	"strconv"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// UnionGenerator handles test case generation for parameters of several
+// primitive types, such as integer|string from an OpenAPI 3.1 type list or a
+// oneOf of primitive schemas
+type UnionGenerator struct{}
+
+// GenerateTestCases generates test cases for union parameters: a valid value
+// of each member type, a value of none of them, and the empty string when a
+// member is a string
+func (g *UnionGenerator) GenerateTestCases(ctx Context) []TestCase {
+	param := ctx.Param
+	members := strings.Split(param.DataType, "|")
+
+	var testCases []TestCase
+	fragments := enumIDFragments(stringsToValues(members))
+	for i, member := range members {
+		testCases = append(testCases, TestCase{
+			ID:          ctx.BaseID + "_valid_" + fragments[i],
+			Type:        "valid",
+			Expected:    ExpectAccept,
+			Description: "Valid " + member + " value, one of the types " + param.DataType,
+			Value:       sampleValue(member, &param.Constraints),
+		})
+	}
+
+	if invalid, ok := wrongUnionValue(param, param.DataType, &param.Constraints); ok {
+		testCases = append(testCases, TestCase{
+			ID:          ctx.BaseID + "_invalid_input",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: "Value of none of the types " + param.DataType,
+			Value:       invalid,
+		})
+	}
+
+	if takesStrings(param.DataType) {
+		empty := TestCase{
+			ID:          ctx.BaseID + "_empty_string",
+			Type:        "valid",
+			Expected:    ExpectAccept,
+			Description: "Empty string (accepted)",
+			Value:       "",
+		}
+		if reason := emptyStringRejection(param); reason != "" {
+			empty.Type = "invalid"
+			empty.Expected = ExpectReject
+			empty.Description = "Empty string (rejected, " + reason + ")"
+		}
+		testCases = append(testCases, empty)
+	}
+
+	return testCases
+}
+
+// Candidate values for a union violation, in order of preference
+var unionViolations = []interface{}{true, "not-a-union-member", 12345, map[string]interface{}{}, []interface{}{}}
+
+// wrongUnionValue returns a value none of the member types of a union
+// accepts. Where values keep their JSON type that is a value of another type;
+// elsewhere it is text none of the members can parse. ok is false when every
+// candidate is accepted, e.g. text for a union with an unconstrained string.
+func wrongUnionValue(param processor.ParameterCase, dataType string, c *processor.Constraints) (interface{}, bool) {
+	members := strings.Split(dataType, "|")
+	typed := typedValues(param)
+	for _, candidate := range unionViolations {
+		text, scalar := textValue(candidate)
+		if !typed && !scalar {
+			continue
+		}
+		accepted := false
+		for _, member := range members {
+			if typed {
+				accepted = accepted || jsonType(candidate) == jsonType(sampleValue(member, c))
+			} else {
+				accepted = accepted || acceptsText(member, c, text)
+			}
+		}
+		switch {
+		case accepted:
+		case typed:
+			return candidate, true
+		default:
+			return text, true
+		}
+	}
+	return nil, false
+}
+
+// textValue renders a scalar candidate as it is sent outside JSON bodies
+func textValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	}
+	return "", false
+}
+
+// acceptsText reports whether a primitive type parses a text value
+func acceptsText(dataType string, c *processor.Constraints, value string) bool {
+	switch dataType {
+	case "integer":
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	case "number":
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	case "boolean":
+		return value == "true" || value == "false"
+	}
+	return acceptsString(dataType, nil, c, value)
+}
@@ -0,0 +1,78 @@
+package generators
+
+import (
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestUnionCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		param processor.ParameterCase
+		want  map[string]interface{} // case ID suffix to value, nil when the case is left out
+	}{
+		{
+			name:  "json body",
+			param: processor.ParameterCase{ParamIn: "body", MediaType: "application/json", DataType: "integer|string"},
+			want: map[string]interface{}{
+				"_valid_integer": int64(1),
+				"_valid_string":  "valid",
+				"_invalid_input": true,
+				"_empty_string":  "",
+			},
+		},
+		{
+			name:  "query parameter without a string member",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "integer|boolean"},
+			want: map[string]interface{}{
+				"_valid_integer": int64(1),
+				"_valid_boolean": true,
+				"_invalid_input": "not-a-union-member",
+				"_empty_string":  nil,
+			},
+		},
+		{
+			name:  "query parameter with an unconstrained string member",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "integer|string"},
+			want:  map[string]interface{}{"_invalid_input": nil, "_empty_string": ""},
+		},
+		{
+			name: "query parameter with a constrained string member",
+			param: processor.ParameterCase{ParamIn: "query", DataType: "integer|string",
+				Constraints: processor.Constraints{Pattern: "^[0-9]+$"}},
+			want: map[string]interface{}{"_invalid_input": "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases := casesByID("u", (&UnionGenerator{}).GenerateTestCases(Context{BaseID: "u", Param: tt.param}))
+			for id, want := range tt.want {
+				tc, ok := cases[id]
+				if want == nil {
+					if ok {
+						t.Errorf("%s: unexpected case with value %s", id, tc.FormatValue())
+					}
+					continue
+				}
+				if !ok || tc.Value != want {
+					t.Errorf("%s: got %#v, want %#v", id, tc.Value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestUnionRegistration(t *testing.T) {
+	cases := casesByID("u", GenerateTestCasesForParameter(Context{
+		BaseID: "u",
+		Param:  processor.ParameterCase{ParamIn: "body", MediaType: "application/json", DataType: "integer|string"},
+	}))
+	if _, ok := cases["_valid_integer"]; !ok {
+		t.Error("union cases not generated for integer|string")
+	}
+	if _, ok := cases["_boundary_min"]; ok {
+		t.Error("integer cases generated for integer|string")
+	}
+}
@@ -0,0 +1,247 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"openapi-tester/spec"
+)
+
+// variantPayload is the valid payload of one variant of a group
+type variantPayload struct {
+	variant processor.Variant
+	fields  map[string]processor.ParameterCase // direct fields by property name
+	value   map[string]interface{}
+}
+
+// GenerateVariantTestCases generates test cases for a polymorphic (oneOf/anyOf)
+// body field: one valid case per variant, a discriminator mismatch case when a
+// discriminator selects the variant, and a payload matching several variants.
+// Payloads are built from the fields of each variant found among cases: its
+// required fields with sample values, and the discriminator property set to
+// the variant's value. names are the ID fragments of the variants.
+func GenerateVariantTestCases(baseID string, group processor.VariantGroup, names []string, cases []processor.ParameterCase) []TestCase {
+	payloads := make([]variantPayload, len(group.Variants))
+	var testCases []TestCase
+	for i, v := range group.Variants {
+		payloads[i] = newVariantPayload(group, v, cases)
+		testCases = append(testCases, TestCase{
+			ID:          baseID + "_variant_" + names[i] + "_valid",
+			Type:        "valid",
+			Expected:    ExpectAccept,
+			Description: "Valid payload for variant " + v.Name,
+			Value:       payloads[i].value,
+		})
+	}
+
+	if group.Discriminator != "" && len(payloads) > 0 {
+		testCases = append(testCases, discriminatorMismatchCase(baseID, group.Discriminator, payloads))
+	}
+
+	// A discriminator settles which variant a payload is, so only groups
+	// without one can be ambiguous. A payload valid against several variants
+	// breaks oneOf but satisfies anyOf.
+	if group.Discriminator != "" {
+		return testCases
+	}
+	if value, a, b, ok := ambiguousPayload(payloads); ok {
+		ambiguous := TestCase{
+			ID:          baseID + "_ambiguous_payload",
+			Type:        "invalid",
+			Expected:    ExpectReject,
+			Description: fmt.Sprintf("Payload matching both oneOf variants %s and %s", a, b),
+			Value:       value,
+		}
+		if group.Kind == "anyOf" {
+			ambiguous.Type = "valid"
+			ambiguous.Expected = ExpectAccept
+			ambiguous.Description = fmt.Sprintf("Payload matching both anyOf variants %s and %s", a, b)
+		}
+		testCases = append(testCases, ambiguous)
+	}
+
+	return testCases
+}
+
+// newVariantPayload collects the direct fields of a variant and builds its
+// valid payload
+func newVariantPayload(group processor.VariantGroup, v processor.Variant, cases []processor.ParameterCase) variantPayload {
+	variant := v.Name
+	if group.Variant != "" {
+		variant = group.Variant + "." + v.Name
+	}
+	prefix := ""
+	if group.Field != "" {
+		prefix = group.Field + "."
+	}
+
+	p := variantPayload{variant: v, fields: map[string]processor.ParameterCase{}, value: map[string]interface{}{}}
+	for _, c := range cases {
+		if c.ParamIn != "body" || c.MediaType != group.MediaType || c.Variant != variant || !strings.HasPrefix(c.ParamName, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(c.ParamName, prefix)
+		if strings.ContainsAny(name, ".[") {
+			continue
+		}
+		p.fields[name] = c
+		if c.Required {
+			p.value[name] = fieldSample(c)
+		}
+	}
+	if group.Discriminator != "" {
+		p.value[group.Discriminator] = discriminatorValue(v)
+	}
+	return p
+}
+
+// fieldSample returns a valid value of a body field: its first enum value, or
+// its example, default or a sample of its type
+func fieldSample(c processor.ParameterCase) interface{} {
+	if len(c.EnumValues) > 0 {
+		return c.EnumValues[0]
+	}
+	return specValue(c.Constraints, sampleValue(c.DataType, &c.Constraints))
+}
+
+// discriminatorValue returns the value of the discriminator property that
+// selects a variant
+func discriminatorValue(v processor.Variant) string {
+	if v.DiscriminatorValue != "" {
+		return v.DiscriminatorValue
+	}
+	return v.Name
+}
+
+// discriminatorMismatchCase sends the payload of one variant with the
+// discriminator naming another variant whose schema the payload breaks: a
+// required field it lacks, or a field it holds with another type. When every
+// variant accepts the payloads of the others, the discriminator names no
+// variant at all.
+func discriminatorMismatchCase(baseID, discriminator string, payloads []variantPayload) TestCase {
+	tc := TestCase{
+		ID:       baseID + "_discriminator_mismatch",
+		Type:     "invalid",
+		Expected: ExpectReject,
+	}
+	for _, a := range payloads {
+		for _, b := range payloads {
+			if a.variant.Name == b.variant.Name {
+				continue
+			}
+			if reason, ok := breaksVariant(a, b, discriminator); ok {
+				tc.Description = fmt.Sprintf("Payload of variant %s with discriminator %s naming %s, %s", a.variant.Name, discriminator, b.variant.Name, reason)
+				tc.Value = withField(a.value, discriminator, discriminatorValue(b.variant))
+				return tc
+			}
+		}
+	}
+
+	tc.Description = fmt.Sprintf("Payload of variant %s with discriminator %s naming no variant", payloads[0].variant.Name, discriminator)
+	tc.Value = withField(payloads[0].value, discriminator, "not_a_variant")
+	return tc
+}
+
+// breaksVariant explains why the payload of variant a is invalid for variant b
+func breaksVariant(a, b variantPayload, discriminator string) (string, bool) {
+	for _, name := range sortedFieldNames(b.fields) {
+		field := b.fields[name]
+		if name == discriminator {
+			continue
+		}
+		value, present := a.value[name]
+		if field.Required && !present {
+			return "which requires " + name, true
+		}
+		if present && !fieldAccepts(field, value) {
+			return "whose " + name + " is of type " + field.DataType, true
+		}
+	}
+	return "", false
+}
+
+// ambiguousPayload merges the payloads of the first two variants whose
+// fields agree on each other's values, so the result is valid for both
+func ambiguousPayload(payloads []variantPayload) (map[string]interface{}, string, string, bool) {
+	for i, a := range payloads {
+		for _, b := range payloads[i+1:] {
+			merged := copyPayload(a.value)
+			for name, value := range b.value {
+				if _, exists := merged[name]; !exists {
+					merged[name] = value
+				}
+			}
+			if acceptsPayload(a, merged) && acceptsPayload(b, merged) {
+				return merged, a.variant.Name, b.variant.Name, true
+			}
+		}
+	}
+	return nil, "", "", false
+}
+
+// acceptsPayload reports whether every field of a variant accepts the value
+// the payload holds for it
+func acceptsPayload(p variantPayload, payload map[string]interface{}) bool {
+	for name, field := range p.fields {
+		if value, present := payload[name]; present && !fieldAccepts(field, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldAccepts reports whether a field accepts a sample value: one of its enum
+// values, or a value of its type
+func fieldAccepts(field processor.ParameterCase, value interface{}) bool {
+	if len(field.EnumValues) > 0 {
+		for _, v := range field.EnumValues {
+			if fmt.Sprint(v) == fmt.Sprint(value) {
+				return true
+			}
+		}
+		return false
+	}
+	return jsonType(value) == jsonType(sampleValue(field.DataType, &field.Constraints))
+}
+
+// jsonType names the JSON type of a sample value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// withField copies a payload with one property set
+func withField(payload map[string]interface{}, name string, value interface{}) map[string]interface{} {
+	out := copyPayload(payload)
+	out[name] = value
+	return out
+}
+
+func copyPayload(payload map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(payload)+1)
+	for k, v := range payload {
+		out[k] = v
+	}
+	return out
+}
+
+func sortedFieldNames(fields map[string]processor.ParameterCase) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
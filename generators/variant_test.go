@@ -0,0 +1,109 @@
+package generators
+
+import (
+	"reflect"
+	"testing"
+
+	"openapi-tester/spec"
+)
+
+func TestVariantCases(t *testing.T) {
+	field := func(name, variant, dataType string, required bool) processor.ParameterCase {
+		return processor.ParameterCase{ParamName: name, ParamIn: "body", MediaType: "application/json", Variant: variant, DataType: dataType, Required: required}
+	}
+	pets := processor.VariantGroup{Kind: "oneOf", Discriminator: "petType", MediaType: "application/json",
+		Variants: []processor.Variant{{Name: "cat", DiscriminatorValue: "cat"}, {Name: "dog", DiscriminatorValue: "dog"}}}
+	untagged := func(kind string) processor.VariantGroup {
+		return processor.VariantGroup{Kind: kind, MediaType: "application/json", Variants: []processor.Variant{{Name: "A"}, {Name: "B"}}}
+	}
+
+	tests := []struct {
+		name  string
+		group processor.VariantGroup
+		cases []processor.ParameterCase
+		want  map[string]TestCase // by case ID suffix, with Description left out
+	}{
+		{
+			name:  "discriminator naming a variant the payload breaks",
+			group: pets,
+			cases: []processor.ParameterCase{
+				field("petType", "cat", "string", true), field("meow", "cat", "boolean", true),
+				field("petType", "dog", "string", true), field("bark", "dog", "boolean", true),
+			},
+			want: map[string]TestCase{
+				"_variant_cat_valid":      {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"petType": "cat", "meow": true}},
+				"_variant_dog_valid":      {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"petType": "dog", "bark": true}},
+				"_discriminator_mismatch": {Type: "invalid", Expected: ExpectReject, Value: map[string]interface{}{"petType": "dog", "meow": true}},
+			},
+		},
+		{
+			name:  "discriminator where every variant accepts the other payloads",
+			group: pets,
+			cases: []processor.ParameterCase{field("meow", "cat", "boolean", false), field("bark", "dog", "boolean", false)},
+			want: map[string]TestCase{
+				"_variant_cat_valid":      {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"petType": "cat"}},
+				"_variant_dog_valid":      {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"petType": "dog"}},
+				"_discriminator_mismatch": {Type: "invalid", Expected: ExpectReject, Value: map[string]interface{}{"petType": "not_a_variant"}},
+			},
+		},
+		{
+			name:  "oneOf payload matching both variants",
+			group: untagged("oneOf"),
+			cases: []processor.ParameterCase{field("a", "A", "string", true), field("b", "B", "integer", true)},
+			want: map[string]TestCase{
+				"_variant_A_valid":   {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"a": "valid"}},
+				"_variant_B_valid":   {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"b": int64(1)}},
+				"_ambiguous_payload": {Type: "invalid", Expected: ExpectReject, Value: map[string]interface{}{"a": "valid", "b": int64(1)}},
+			},
+		},
+		{
+			name:  "anyOf payload matching both variants",
+			group: untagged("anyOf"),
+			cases: []processor.ParameterCase{field("a", "A", "string", true), field("b", "B", "integer", true)},
+			want: map[string]TestCase{
+				"_variant_A_valid":   {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"a": "valid"}},
+				"_variant_B_valid":   {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"b": int64(1)}},
+				"_ambiguous_payload": {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"a": "valid", "b": int64(1)}},
+			},
+		},
+		{
+			name:  "variants disagreeing on a field type",
+			group: untagged("oneOf"),
+			cases: []processor.ParameterCase{field("v", "A", "string", true), field("v", "B", "integer", true)},
+			want: map[string]TestCase{
+				"_variant_A_valid": {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"v": "valid"}},
+				"_variant_B_valid": {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"v": int64(1)}},
+			},
+		},
+		{
+			name: "group nested in a field of another variant",
+			group: processor.VariantGroup{Field: "shape", Variant: "outer", Kind: "oneOf", MediaType: "application/json",
+				Variants: []processor.Variant{{Name: "A"}, {Name: "B"}}},
+			cases: []processor.ParameterCase{
+				field("shape.v", "outer.A", "string", true), field("shape.v", "outer.B", "boolean", true),
+				field("shape.v.deep", "outer.A", "string", true), field("v", "outer.A", "integer", true),
+			},
+			want: map[string]TestCase{
+				"_variant_A_valid": {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"v": "valid"}},
+				"_variant_B_valid": {Type: "valid", Expected: ExpectAccept, Value: map[string]interface{}{"v": true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := make([]string, len(tt.group.Variants))
+			for i, v := range tt.group.Variants {
+				names[i] = v.Name
+			}
+			got := casesByID("v", GenerateVariantTestCases("v", tt.group, names, tt.cases))
+			for id, tc := range got {
+				tc.ID, tc.Description = "", ""
+				got[id] = tc
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -15,54 +16,216 @@ import (
 // Processing logic is now in the processor package
 // --------------------------
 
-// generateEndpointAccessTestID creates a basic test case ID for accessing an endpoint
-func generateEndpointAccessTestID(endpoint, method string) string {
+// combinations configures the combinatorial endpoint cases, set from the command line
+var combinations generators.CombinationOptions
+
+// includeBasePath puts the base path of the default server in front of the
+// endpoint path in test IDs and request URLs, set from the command line
+var includeBasePath bool
+
+// endpointPath returns the path an endpoint is identified by: its path, after
+// the base path of its default server when includeBasePath is set
+func endpointPath(ep processor.EndpointCases) string {
+	if includeBasePath && len(ep.Servers) > 0 {
+		return ep.Servers[0].BasePath + ep.Endpoint
+	}
+	return ep.Endpoint
+}
+
+// endpointBaseID creates the base of endpoint-level test IDs: endpoint_method
+func endpointBaseID(endpoint, method string) string {
 	// Clean endpoint path for use in test ID (remove leading slash, replace slashes with underscores)
 	endpointClean := strings.TrimPrefix(endpoint, "/")
 	endpointClean = strings.ReplaceAll(endpointClean, "/", "_")
 	endpointClean = strings.ReplaceAll(endpointClean, "{", "")
 	endpointClean = strings.ReplaceAll(endpointClean, "}", "")
 
-	return fmt.Sprintf("%s_%s_basic_access", endpointClean, strings.ToLower(method))
+	return fmt.Sprintf("%s_%s", endpointClean, strings.ToLower(method))
+}
+
+// generateEndpointAccessTestID creates a basic test case ID for accessing an endpoint
+func generateEndpointAccessTestID(endpoint, method string) string {
+	return endpointBaseID(endpoint, method) + "_basic_access"
+}
+
+// idFragment replaces every character that is not a letter or digit with an
+// underscore so the value can be used inside a test ID
+func idFragment(value string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, value)
 }
 
-// generateTestCaseIDs creates test case identifiers for a parameter
-func generateTestCaseIDs(endpoint, method string, param processor.ParameterCase) []string {
+// mediaTypeIDFragment names a media type for use in a test ID, e.g.
+// multipart/form-data becomes form_data and application/vnd.api+json vnd_api_json
+func mediaTypeIDFragment(mediaType string) string {
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	if i := strings.Index(mediaType, "/"); i >= 0 {
+		mediaType = mediaType[i+1:]
+	}
+	return idFragment(strings.TrimSpace(mediaType))
+}
+
+// fieldBaseID creates the base test ID for a parameter or body field:
+// endpoint[_mediatype][_variant][_field]
+func fieldBaseID(ep processor.EndpointCases, mediaType, variant, field string) string {
 	// Clean endpoint path for use in test ID (remove leading slash, replace slashes with underscores)
-	endpointClean := strings.TrimPrefix(endpoint, "/")
+	endpointClean := strings.TrimPrefix(endpointPath(ep), "/")
 	endpointClean = strings.ReplaceAll(endpointClean, "/", "_")
 	endpointClean = strings.ReplaceAll(endpointClean, "{", "")
 	endpointClean = strings.ReplaceAll(endpointClean, "}", "")
 
+	parts := []string{endpointClean}
+
+	// Fields of additional body media types are told apart from the preferred one
+	// by the media type, e.g. pet_xml_name next to pet_name
+	if mediaType != "" && len(ep.MediaTypes) > 1 && mediaType != ep.MediaTypes[0] {
+		parts = append(parts, mediaTypeIDFragment(mediaType))
+	}
+
+	// Fields of oneOf/anyOf variants carry the variant name, e.g. pets_Cat_name
+	if variant != "" {
+		parts = append(parts, idFragment(variant))
+	}
+
+	// Nested body fields (address.city, items[].sku) become address_city, items_sku
+	if field != "" {
+		fieldClean := strings.ReplaceAll(field, "[]", "")
+		fieldClean = strings.ReplaceAll(fieldClean, ".", "_")
+		parts = append(parts, fieldClean)
+	}
+
+	return strings.Join(parts, "_")
+}
+
+// generateParameterTestCases creates the test cases for a parameter or body field
+func generateParameterTestCases(ep processor.EndpointCases, param processor.ParameterCase) []generators.TestCase {
 	// Base test ID: endpoint_paramname
-	baseID := fmt.Sprintf("%s_%s", endpointClean, param.ParamName)
+	baseID := fieldBaseID(ep, param.MediaType, param.Variant, param.ParamName)
 
 	// Use the generators package to create test cases
-	testCases := generators.GenerateTestCasesForType(baseID, param.DataType, param.EnumValues)
+	return generators.GenerateTestCasesForParameter(generators.Context{
+		BaseID:   baseID,
+		Param:    param,
+		Endpoint: ep,
+	})
+}
+
+// generateVariantTestCases creates the test cases for a oneOf/anyOf body field
+func generateVariantTestCases(ep processor.EndpointCases, group processor.VariantGroup) []generators.TestCase {
+	baseID := fieldBaseID(ep, group.MediaType, group.Variant, group.Field)
+
+	var names []string
+	for _, v := range group.Variants {
+		names = append(names, idFragment(v.Name))
+	}
+	return generators.GenerateVariantTestCases(baseID, group, names, ep.Cases)
+}
+
+// generateEndpointTestCases creates every test case of an endpoint: basic
+// access, server, response and auth cases, parameter and body field cases, and
+// polymorphic body cases
+func generateEndpointTestCases(ep processor.EndpointCases) []generators.TestCase {
+	// Generate basic endpoint access test case
+	access := generators.TestCase{
+		ID:          generateEndpointAccessTestID(endpointPath(ep), ep.Method),
+		Type:        "valid",
+		Expected:    generators.ExpectAccept,
+		Description: "Basic endpoint access",
+	}
+	// With the base path included, the case carries the request URL
+	if includeBasePath && len(ep.Servers) > 0 {
+		access.Value = ep.Servers[0].URL + ep.Endpoint
+	}
+	testCases := []generators.TestCase{access}
+
+	// Generate a case per server, including each value of server variable enums
+	baseID := endpointBaseID(endpointPath(ep), ep.Method)
+	testCases = append(testCases, generators.GenerateServerTestCases(baseID, ep)...)
+
+	// Generate a case per documented response
+	testCases = append(testCases, generators.GenerateResponseTestCases(baseID, ep)...)
 
-	// Extract just the IDs from the test cases
-	var testIDs []string
-	for _, tc := range testCases {
-		testIDs = append(testIDs, tc.ID)
+	// Generate authentication and authorization cases
+	testCases = append(testCases, generators.GenerateAuthTestCases(baseID, ep)...)
+
+	// Generate parameter-specific test cases
+	var combinationParams []generators.CombinationParameter
+	for _, c := range ep.Cases {
+		paramCases := generateParameterTestCases(ep, c)
+		testCases = append(testCases, paramCases...)
+
+		if combinable(ep, c) {
+			combinationParams = append(combinationParams, generators.CombinationParameter{
+				Name:   c.ParamIn + "." + c.ParamName,
+				Values: generators.CombinationValues(paramCases),
+			})
+		}
 	}
 
-	return testIDs
+	// Generate polymorphic body test cases
+	for _, group := range ep.Variants {
+		testCases = append(testCases, generateVariantTestCases(ep, group)...)
+	}
+
+	// Generate pairwise/n-wise combinations of parameter values
+	testCases = append(testCases, generators.GenerateCombinationTestCases(baseID, combinationParams, combinations)...)
+
+	return testCases
+}
+
+// combinable reports whether a parameter takes part in combination cases:
+// parameters and body fields of the preferred media type outside variants.
+// Objects and array item fields are left out, their own fields and the
+// enclosing array carry their values.
+func combinable(ep processor.EndpointCases, param processor.ParameterCase) bool {
+	if param.Variant != "" || param.DataType == "object" || strings.Contains(param.ParamName, "[]") {
+		return false
+	}
+	return param.MediaType == "" || len(ep.MediaTypes) == 0 || param.MediaType == ep.MediaTypes[0]
 }
 
 func main() {
-	if len(os.Args) < 2 || len(os.Args) > 3 {
+	security := flag.Bool("security", false, "also generate security payload test cases")
+	flag.IntVar(&combinations.Strength, "combinations", 0, "generate n-wise parameter combinations, 2 for pairwise")
+	flag.Int64Var(&combinations.Seed, "seed", 1, "seed for the parameter combinations")
+	flag.IntVar(&combinations.MaxCases, "max-combinations", 50, "maximum combination cases per endpoint, 0 for no limit")
+	flag.BoolVar(&includeBasePath, "base-path", false, "include the server base path in test IDs and request URLs")
+	flag.Usage = func() {
 		fmt.Println("Usage:")
-		fmt.Println("  openapi-casegen <openapi-spec-file>                    # Generate test cases")
-		fmt.Println("  openapi-casegen <openapi-spec-file> <junit-xml-file>   # Validate tests against JUnit XML")
+		fmt.Println("  openapi-casegen [flags] <openapi-spec-file>                    # Generate test cases")
+		fmt.Println("  openapi-casegen [flags] <openapi-spec-file> <junit-xml-file>   # Validate tests against JUnit XML")
 		fmt.Println("")
-		fmt.Println("Supports OpenAPI 3.0 and Swagger 2.0 specifications")
+		fmt.Println("Supports OpenAPI 3.0, OpenAPI 3.1 and Swagger 2.0 specifications")
+		fmt.Println("Flags:")
+		fmt.Println("  -security              Also generate security payload test cases (injection, XSS, SSRF, ...)")
+		fmt.Println("  -combinations n        Also generate n-wise parameter combinations, 2 for pairwise")
+		fmt.Println("  -seed n                Seed for the parameter combinations (default 1)")
+		fmt.Println("  -max-combinations n    Maximum combination cases per endpoint (default 50, 0 for no limit)")
+		fmt.Println("  -base-path             Include the server base path (basePath, servers) in test IDs and request URLs")
 		fmt.Println("Examples:")
 		fmt.Println("  openapi-casegen openapi.yaml")
-		fmt.Println("  openapi-casegen openapi.yaml results.xml")
+		fmt.Println("  openapi-casegen -security openapi.yaml results.xml")
+		fmt.Println("  openapi-casegen -combinations 2 -seed 42 openapi.yaml")
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 || len(args) > 2 {
+		flag.Usage()
 		os.Exit(1)
 	}
 
-	specFile := os.Args[1]
+	if *security {
+		generators.Enable(generators.BuiltinSecurity)
+	}
+
+	specFile := args[0]
 
 	// Detect specification format and get appropriate processor
 	version, err := processor.DetectSpecVersion(specFile)
@@ -79,25 +242,30 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to process specification: %v", err)
 	}
+	if reporter, ok := processor.(interface{ Warnings() []string }); ok {
+		for _, warning := range reporter.Warnings() {
+			log.Printf("warning: %s", warning)
+		}
+	}
 
-	// Collect all generated test case IDs
-	var generatedTestIDs []string
+	// Collect all generated test cases
+	var generatedTests []validator.GeneratedTest
 	for _, ep := range endpoints {
-		// Generate basic endpoint access test case
-		endpointTestID := generateEndpointAccessTestID(ep.Endpoint, ep.Method)
-		generatedTestIDs = append(generatedTestIDs, endpointTestID)
-
-		// Generate parameter-specific test cases
-		for _, c := range ep.Cases {
-			testCaseIDs := generateTestCaseIDs(ep.Endpoint, ep.Method, c)
-			generatedTestIDs = append(generatedTestIDs, testCaseIDs...)
+		for _, tc := range generateEndpointTestCases(ep) {
+			generatedTests = append(generatedTests, validator.GeneratedTest{
+				ID:          tc.ID,
+				Type:        tc.Type,
+				Expected:    tc.Expected,
+				Value:       tc.FormatValue(),
+				Description: tc.Description,
+			})
 		}
 	}
 
 	// Check if validation mode is requested
-	if len(os.Args) == 3 {
-		xmlFile := os.Args[2]
-		validateTests(generatedTestIDs, xmlFile)
+	if len(args) == 2 {
+		xmlFile := args[1]
+		validateTests(generatedTests, xmlFile)
 	} else {
 		printGeneratedTests(endpoints)
 	}
@@ -106,23 +274,20 @@ func main() {
 func printGeneratedTests(endpoints []processor.EndpointCases) {
 	fmt.Println("===== Generated Test Case IDs =====")
 	for _, ep := range endpoints {
-		fmt.Printf("\n[%s] %s\n", ep.Method, ep.Endpoint)
-
-		// Generate basic endpoint access test case
-		endpointTestID := generateEndpointAccessTestID(ep.Endpoint, ep.Method)
-		fmt.Printf("- %s\n", endpointTestID)
+		fmt.Printf("\n[%s] %s\n", ep.Method, endpointPath(ep))
 
-		// Generate parameter-specific test cases
-		for _, c := range ep.Cases {
-			testCaseIDs := generateTestCaseIDs(ep.Endpoint, ep.Method, c)
-			for _, testID := range testCaseIDs {
-				fmt.Printf("- %s\n", testID)
+		// Each case is printed with its expected outcome and input value
+		for _, tc := range generateEndpointTestCases(ep) {
+			line := fmt.Sprintf("- %s [%s]", tc.ID, tc.Expected)
+			if value := tc.FormatValue(); value != "" {
+				line += " " + value
 			}
+			fmt.Println(line)
 		}
 	}
 }
 
-func validateTests(generatedTestIDs []string, xmlFile string) {
+func validateTests(generatedTests []validator.GeneratedTest, xmlFile string) {
 	v := validator.NewValidator()
 
 	// Load test results from XML
@@ -132,7 +297,7 @@ func validateTests(generatedTestIDs []string, xmlFile string) {
 	}
 
 	// Compare generated tests with actual tests
-	result := v.CompareTests(generatedTestIDs, actualTests)
+	result := v.CompareTests(generatedTests, actualTests)
 
 	// Print validation report
 	v.PrintReport(result)
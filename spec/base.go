@@ -1,9 +1,19 @@
 package processor
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // SpecProcessor defines the interface for processing API specifications
@@ -13,57 +23,613 @@ type SpecProcessor interface {
 
 // EndpointCases represents a collection of test cases for an endpoint
 type EndpointCases struct {
-	Endpoint string
-	Method   string
-	Cases    []ParameterCase
+	Endpoint   string
+	Method     string
+	MediaTypes []string // request body media types, preferred (JSON) first
+	Cases      []ParameterCase
+	Variants   []VariantGroup // oneOf/anyOf compositions in the request body
+	Responses  []ResponseCase // documented responses, ordered by status code
+	// Security lists the alternative ways of authenticating, empty when the
+	// operation is not secured
+	Security []SecurityRequirement
+	// AnonymousAllowed is set when the operation opts out of security with
+	// security: [] or lists an empty requirement among its alternatives
+	AnonymousAllowed bool
+	// Servers lists the base URLs the endpoint is served under, one per value
+	// of enumerated server variables, the default server first
+	Servers []Server
+}
+
+// Server is a base URL with its variables substituted
+type Server struct {
+	URL       string            // without a trailing slash, e.g. https://eu.api.example.com/v1
+	BasePath  string            // path of URL without a trailing slash, e.g. /v1
+	Variables map[string]string // values substituted for the server variables
+}
+
+// SecurityRequirement is one way of authenticating an operation, every scheme
+// in it must be satisfied together
+type SecurityRequirement struct {
+	Schemes []SecurityScheme
+}
+
+// SecurityScheme is a security scheme as applied to an operation
+type SecurityScheme struct {
+	Name      string   // key in securitySchemes/securityDefinitions
+	Type      string   // apiKey, http, oauth2 or openIdConnect; Swagger 2.0 basic becomes http
+	Scheme    string   // HTTP authentication scheme such as basic or bearer
+	In        string   // where an apiKey is sent: header, query or cookie
+	ParamName string   // name of the apiKey header, query parameter or cookie
+	Scopes    []string // OAuth2/OpenID Connect scopes the operation requires
+}
+
+// ResponseCase describes a documented response of an operation
+type ResponseCase struct {
+	StatusCode     string // e.g. 200, 404, 4XX or default
+	Description    string
+	MediaTypes     []string // response media types, preferred (JSON) first
+	RequiredFields []string // required fields of the preferred response body, dotted like body fields
+	Headers        []string // declared response headers, sorted
+}
+
+// VariantGroup describes a polymorphic request body field: a oneOf or anyOf,
+// or a schema with a discriminator that other schemas extend through allOf
+type VariantGroup struct {
+	Field         string // dotted path of the composed field, "" when the body itself is composed
+	Variant       string // variant enclosing this group, "" at the top level
+	Kind          string // oneOf or anyOf
+	Discriminator string // discriminator property name, "" when there is none
+	MediaType     string
+	Variants      []Variant
+}
+
+// Variant is one alternative of a VariantGroup. Its fields are the cases whose
+// ParameterCase.Variant names it.
+type Variant struct {
+	Name               string // discriminator value, schema name, title or variantN
+	DiscriminatorValue string
 }
 
 // ParameterCase represents a single parameter with its test case information
 type ParameterCase struct {
-	ParamName   string
+	ParamName   string // body fields use a dotted path such as address.city or items[].sku
 	ParamIn     string // path, query, header, cookie, body, formData
 	Required    bool
 	EnumValues  []interface{}
 	Description string
-	DataType    string
+	DataType    string // primitive type, array[<item type>], object, or a union such as integer|string
+	Nullable    bool
+	MediaType   string   // request body media type for body and formData fields
+	FileTypes   []string // content types accepted for a file upload, empty when any is
+	Variant     string   // oneOf/anyOf variant the field belongs to, nested variants joined by "."
+	Constraints Constraints
+	Extensions  map[string]interface{} // x- vendor extensions of the parameter and its schema
+
+	// Serialization of path, query, header and cookie parameters (OpenAPI 3).
+	// Style and Explode hold the defaults for the location when not declared.
+	Style         string // form, simple, label, matrix, spaceDelimited, pipeDelimited or deepObject
+	Explode       bool
+	AllowReserved bool // reserved characters may be sent unencoded in the query
+	// CollectionFormat is the Swagger 2.0 serialization of array parameters:
+	// csv (the default), ssv, tsv, pipes or multi
+	CollectionFormat string
+}
+
+// Constraints holds the validation keywords and annotations of a parameter or
+// body field schema. Pointers are nil when the keyword is absent.
+type Constraints struct {
+	Minimum              *float64
+	Maximum              *float64
+	ExclusiveMinimum     bool
+	ExclusiveMaximum     bool
+	MultipleOf           *float64
+	MinLength            *int64
+	MaxLength            *int64
+	Pattern              string
+	Format               string
+	MinItems             *int64
+	MaxItems             *int64
+	UniqueItems          bool
+	Items                *Constraints      // constraints of array items
+	Properties           map[string]string // object property names and their data types
+	Required             []string          // required object properties
+	AdditionalProperties *bool             // false when the object rejects undeclared properties
+	Default              interface{}
+	Example              interface{}
+	ReadOnly             bool
+	WriteOnly            bool
+	Deprecated           bool
+}
+
+// DefaultMaxBodyDepth is how many levels of nested request body fields are
+// extracted when a processor does not set MaxDepth
+const DefaultMaxBodyDepth = 5
+
+// bodyDepth returns the configured nesting limit or the default
+func bodyDepth(maxDepth int) int {
+	if maxDepth <= 0 {
+		return DefaultMaxBodyDepth
+	}
+	return maxDepth
 }
 
-// DetectSpecVersion determines if a file is Swagger 2.0 or OpenAPI 3.0
-func DetectSpecVersion(path string) (string, error) {
+// Specification formats recognised by DetectSpecVersion
+const (
+	FormatSwagger2  = "swagger2"
+	FormatOpenAPI30 = "openapi3"
+	FormatOpenAPI31 = "openapi31"
+)
+
+// SpecVersion identifies the format of a specification and its declared version
+type SpecVersion struct {
+	Format  string // swagger2, openapi3, openapi31
+	Version string // version string as declared in the document, e.g. 2.0, 3.0.3
+}
+
+// String returns a human readable name for the specification version
+func (v SpecVersion) String() string {
+	switch v.Format {
+	case FormatSwagger2:
+		return "Swagger " + v.Version
+	case FormatOpenAPI30, FormatOpenAPI31:
+		return "OpenAPI " + v.Version
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	openAPI30Version = regexp.MustCompile(`^3\.0(\.\d+)?$`)
+	openAPI31Version = regexp.MustCompile(`^3\.1(\.\d+)?$`)
+)
+
+// DetectSpecVersion determines whether a file is Swagger 2.0, OpenAPI 3.0 or OpenAPI 3.1
+// by parsing it and reading its `swagger` or `openapi` version field
+func DetectSpecVersion(path string) (SpecVersion, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return "", err
+		return SpecVersion{}, err
 	}
 
-	// Try to parse as JSON first
-	var raw map[string]interface{}
-	if err := json.Unmarshal(data, &raw); err == nil {
-		// Successfully parsed as JSON
-		if _, ok := raw["swagger"]; ok {
-			return "swagger2", nil
-		}
-		if _, ok := raw["openapi"]; ok {
-			return "openapi3", nil
+	raw, err := decodeGenericSpec(data)
+	if err != nil {
+		return SpecVersion{}, fmt.Errorf("%s is neither valid JSON nor YAML: %v", path, err)
+	}
+
+	return versionFromDocument(raw)
+}
+
+// versionFromDocument reads the version field of a parsed specification
+func versionFromDocument(raw map[string]interface{}) (SpecVersion, error) {
+	swaggerField, hasSwagger := raw["swagger"]
+	openapiField, hasOpenAPI := raw["openapi"]
+
+	switch {
+	case hasSwagger && hasOpenAPI:
+		return SpecVersion{}, fmt.Errorf("document declares both 'swagger' and 'openapi'")
+
+	case hasSwagger:
+		version := fmt.Sprint(scalarToString(swaggerField))
+		if version != "2.0" {
+			return SpecVersion{}, fmt.Errorf("unsupported Swagger version %q, only 2.0 is supported", version)
 		}
-	} else {
-		// Not valid JSON, check if it's YAML with openapi
-		content := string(data)
-		if len(content) > 10 && (content[:7] == "openapi" || (len(content) > 10 && content[:10] == "openapi:")) {
-			return "openapi3", nil
+		return SpecVersion{Format: FormatSwagger2, Version: version}, nil
+
+	case hasOpenAPI:
+		version := fmt.Sprint(scalarToString(openapiField))
+		switch {
+		case openAPI30Version.MatchString(version):
+			return SpecVersion{Format: FormatOpenAPI30, Version: version}, nil
+		case openAPI31Version.MatchString(version):
+			return SpecVersion{Format: FormatOpenAPI31, Version: version}, nil
+		default:
+			return SpecVersion{}, fmt.Errorf("unsupported OpenAPI version %q, expected 3.0.x or 3.1.x", version)
 		}
 	}
 
-	return "", fmt.Errorf("unable to determine specification format")
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return SpecVersion{}, fmt.Errorf("document has no top-level 'swagger' or 'openapi' field (found: %s)", strings.Join(keys, ", "))
 }
 
 // GetProcessor returns the appropriate processor for the spec version
-func GetProcessor(version string) SpecProcessor {
-	switch version {
-	case "openapi3":
+func GetProcessor(version SpecVersion) SpecProcessor {
+	switch version.Format {
+	case FormatOpenAPI30:
 		return &OpenAPI3Processor{}
-	case "swagger2":
+	case FormatOpenAPI31:
+		return &OpenAPI31Processor{}
+	case FormatSwagger2:
 		return &Swagger2Processor{}
 	default:
 		return nil
 	}
 }
+
+// decodeGenericSpec parses a JSON or YAML specification into a generic map.
+// A leading UTF-8 byte order mark is ignored and numbers keep their literal text.
+func decodeGenericSpec(data []byte) (map[string]interface{}, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	// Try to parse as JSON first
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err == nil {
+		return raw, nil
+	}
+
+	return decodeSpecDocument(data)
+}
+
+// decodeSpecDocument parses a YAML (or JSON) specification into a generic map.
+// Anchors, aliases and merge keys are resolved, empty documents before or
+// after the specification are skipped, and more than one non-empty document
+// is rejected since it is ambiguous which one is the specification.
+func decodeSpecDocument(data []byte) (map[string]interface{}, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var doc *yaml.Node
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(node.Content) == 0 || node.Content[0].ShortTag() == "!!null" {
+			continue
+		}
+		if doc != nil {
+			return nil, fmt.Errorf("multiple YAML documents found (line %d), expected a single specification", node.Line)
+		}
+		doc = &node
+	}
+
+	if doc == nil {
+		return nil, fmt.Errorf("empty specification document")
+	}
+
+	value, err := newYAMLConverter().value(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("specification root must be a mapping")
+	}
+
+	return raw, nil
+}
+
+// maxYAMLAliasNodes caps the nodes produced by expanding aliases, so that
+// nested aliases (an "alias bomb") cannot exhaust memory
+const maxYAMLAliasNodes = 1000000
+
+// yamlConverter converts YAML nodes, guarding alias expansion
+type yamlConverter struct {
+	expanding  map[*yaml.Node]bool // anchors being expanded, to detect aliases to themselves
+	aliasDepth int                 // number of aliases being expanded
+	aliasNodes int                 // nodes produced inside alias expansions
+}
+
+func newYAMLConverter() *yamlConverter {
+	return &yamlConverter{expanding: map[*yaml.Node]bool{}}
+}
+
+// value converts a YAML node into JSON-compatible Go values.
+// Numbers keep their literal text as json.Number so fields such as
+// `swagger: 2.0` or `version: 1.10` can be recovered as strings.
+// An alias that contains itself, or aliases expanding into more than
+// maxYAMLAliasNodes nodes, are errors.
+func (c *yamlConverter) value(node *yaml.Node) (interface{}, error) {
+	if c.aliasDepth > 0 {
+		c.aliasNodes++
+		if c.aliasNodes > maxYAMLAliasNodes {
+			return nil, fmt.Errorf("line %d: aliases expand to more than %d nodes", node.Line, maxYAMLAliasNodes)
+		}
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return c.value(node.Content[0])
+
+	case yaml.AliasNode:
+		if c.expanding[node.Alias] {
+			return nil, fmt.Errorf("line %d: alias *%s refers to a node containing it", node.Line, node.Value)
+		}
+		c.expanding[node.Alias] = true
+		c.aliasDepth++
+		v, err := c.value(node.Alias)
+		c.aliasDepth--
+		delete(c.expanding, node.Alias)
+		return v, err
+
+	case yaml.SequenceNode:
+		out := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			v, err := c.value(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+
+	case yaml.MappingNode:
+		out := map[string]interface{}{}
+		var merges []*yaml.Node
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			if keyNode.Kind == yaml.AliasNode {
+				keyNode = keyNode.Alias
+			}
+			if keyNode.ShortTag() == "!!merge" {
+				merges = append(merges, valueNode)
+				continue
+			}
+			if keyNode.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: unsupported non-scalar mapping key", keyNode.Line)
+			}
+
+			v, err := c.value(valueNode)
+			if err != nil {
+				return nil, err
+			}
+			out[keyNode.Value] = v
+		}
+
+		// Merge keys (<<: *anchor) never override keys set explicitly
+		for _, merge := range merges {
+			sources := []*yaml.Node{merge}
+			if merge.Kind == yaml.SequenceNode {
+				sources = merge.Content
+			}
+			for _, source := range sources {
+				v, err := c.value(source)
+				if err != nil {
+					return nil, err
+				}
+				merged, ok := v.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("line %d: merge key value must be a mapping", source.Line)
+				}
+				for k, mv := range merged {
+					if _, exists := out[k]; !exists {
+						out[k] = mv
+					}
+				}
+			}
+		}
+		return out, nil
+
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!int", "!!float":
+			if json.Valid([]byte(node.Value)) {
+				return json.Number(node.Value), nil
+			}
+		case "!!timestamp":
+			return node.Value, nil
+		}
+
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// parameterKey identifies a parameter within an operation, the spec defines a
+// parameter's identity as the combination of its name and location
+func parameterKey(name, in string) string {
+	return in + ":" + name
+}
+
+// serverBasePath returns the path of a server URL, absolute or relative,
+// without a trailing slash: /v1 for https://api.example.com/v1/
+func serverBasePath(rawURL string) string {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	return strings.TrimSuffix(path, "/")
+}
+
+// baseMediaType lowercases a media type and strips parameters such as charset
+func baseMediaType(mediaType string) string {
+	if i := strings.Index(mediaType, ";"); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// isJSONMediaType reports whether a media type carries JSON, including
+// structured syntax suffixes such as application/vnd.api+json
+func isJSONMediaType(mediaType string) bool {
+	mt := baseMediaType(mediaType)
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+// isUploadMediaType reports whether a media type can carry files: multipart
+// bodies with file parts, or a raw binary body
+func isUploadMediaType(mediaType string) bool {
+	return strings.HasPrefix(baseMediaType(mediaType), "multipart/") || isBinaryMediaType(mediaType)
+}
+
+// isBinaryMediaType reports whether a media type is sent as raw bytes rather
+// than as text or a form, e.g. application/octet-stream, image/png or
+// application/pdf
+func isBinaryMediaType(mediaType string) bool {
+	mt := baseMediaType(mediaType)
+	switch {
+	case mt == "", isJSONMediaType(mt), strings.HasPrefix(mt, "multipart/"), strings.HasPrefix(mt, "text/"):
+		return false
+	case mt == "application/xml", strings.HasSuffix(mt, "+xml"), mt == "application/x-www-form-urlencoded":
+		return false
+	}
+	return true
+}
+
+// splitContentTypes splits a comma-separated list of content types, such as
+// a multipart encoding contentType, leaving out wildcards that accept any type
+func splitContentTypes(list string) []string {
+	var out []string
+	for _, ct := range strings.Split(list, ",") {
+		if ct = baseMediaType(ct); ct != "" && ct != "*/*" && ct != "application/octet-stream" {
+			out = append(out, ct)
+		}
+	}
+	return out
+}
+
+// orderMediaTypes puts application/json first, then other JSON media types,
+// then the rest, keeping the given order within each group
+func orderMediaTypes(mediaTypes []string) []string {
+	ordered := make([]string, 0, len(mediaTypes))
+	for _, mt := range mediaTypes {
+		if baseMediaType(mt) == "application/json" {
+			ordered = append(ordered, mt)
+		}
+	}
+	for _, mt := range mediaTypes {
+		if isJSONMediaType(mt) && baseMediaType(mt) != "application/json" {
+			ordered = append(ordered, mt)
+		}
+	}
+	for _, mt := range mediaTypes {
+		if !isJSONMediaType(mt) {
+			ordered = append(ordered, mt)
+		}
+	}
+	return ordered
+}
+
+// uint64Ptr converts an optional unsigned length or count
+func uint64Ptr(v *uint64) *int64 {
+	if v == nil {
+		return nil
+	}
+	n := clampUint64(*v)
+	return &n
+}
+
+// nonZero converts a length or count whose zero value means absent
+func nonZero(v uint64) *int64 {
+	if v == 0 {
+		return nil
+	}
+	n := clampUint64(v)
+	return &n
+}
+
+// clampUint64 converts a length or count, capping values above the largest
+// int64 instead of letting them wrap around to negative ones
+func clampUint64(v uint64) int64 {
+	if v > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(v)
+}
+
+// joinVariant names a variant nested inside another one
+func joinVariant(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// pinDiscriminator restricts the discriminator property of a variant to the
+// values selecting the variant, so it is not tested as free text
+func pinDiscriminator(cases []ParameterCase, field, variant string, values []interface{}) {
+	for i := range cases {
+		if cases[i].ParamName == field && cases[i].Variant == variant {
+			cases[i].EnumValues = values
+		}
+	}
+}
+
+// Operation methods in the order endpoints are reported
+var canonicalMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}
+
+// sortedKeys returns the keys of a map in lexical order so extraction does not
+// depend on Go's randomised map iteration
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// scalarToString converts a scalar decoded from YAML back into its string form
+func scalarToString(v interface{}) interface{} {
+	switch s := v.(type) {
+	case json.Number:
+		return s.String()
+	case bool:
+		return fmt.Sprint(s)
+	default:
+		return v
+	}
+}
+
+// vendorExtensions collects the x- extensions of a parameter and its schema,
+// earlier sources winning over later ones. It returns nil when there are none.
+func vendorExtensions(sources ...map[string]interface{}) map[string]interface{} {
+	var out map[string]interface{}
+	for _, source := range sources {
+		for name, value := range source {
+			if !strings.HasPrefix(strings.ToLower(name), "x-") {
+				continue
+			}
+			if out == nil {
+				out = map[string]interface{}{}
+			}
+			if _, exists := out[name]; !exists {
+				out[name] = value
+			}
+		}
+	}
+	return out
+}
+
+// sortStatusCodes orders response status codes numerically, ranges such as 4XX
+// after the codes they cover and default last
+func sortStatusCodes(codes []string) {
+	rank := func(code string) int {
+		upper := strings.ToUpper(code)
+		switch {
+		case upper == "DEFAULT":
+			return 1000
+		case len(upper) == 3 && strings.HasSuffix(upper, "XX"):
+			return int(upper[0]-'0')*100 + 99
+		}
+		var n int
+		if _, err := fmt.Sscanf(code, "%d", &n); err != nil {
+			return 999
+		}
+		return n
+	}
+	sort.SliceStable(codes, func(i, j int) bool {
+		ri, rj := rank(codes[i]), rank(codes[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return codes[i] < codes[j]
+	})
+}
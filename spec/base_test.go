@@ -0,0 +1,470 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeGenericSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string // the decoded document as JSON
+		wantErr string // part of the error message, "" when decoding succeeds
+	}{
+		{
+			name: "json keeps numbers as written",
+			data: `{"swagger": "2.0", "info": {"version": 1.10}}`,
+			want: `{"info":{"version":1.10},"swagger":"2.0"}`,
+		},
+		{
+			name: "yaml with a byte order mark and an unquoted version",
+			data: "\xef\xbb\xbfswagger: 2.0\ninfo: {version: 1.10}\n",
+			want: `{"info":{"version":1.10},"swagger":2.0}`,
+		},
+		{
+			name: "anchors and merge keys",
+			data: "base: &base {type: string, minLength: 1}\nname:\n  <<: *base\n  minLength: 2\ncopy: *base\n",
+			want: `{"base":{"minLength":1,"type":"string"},"copy":{"minLength":1,"type":"string"},"name":{"minLength":2,"type":"string"}}`,
+		},
+		{
+			name: "empty documents around the specification",
+			data: "---\n---\nswagger: '2.0'\n---\n",
+			want: `{"swagger":"2.0"}`,
+		},
+		{
+			name:    "several documents",
+			data:    "swagger: '2.0'\n---\nopenapi: 3.0.0\n",
+			wantErr: "multiple YAML documents",
+		},
+		{
+			name:    "alias containing itself",
+			data:    "a: &a\n  b: *a\n",
+			wantErr: "refers to a node containing it",
+		},
+		{
+			name: "alias bomb",
+			data: "a: &a [x, x, x, x, x, x, x, x, x, x]\n" +
+				"b: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a, *a]\n" +
+				"c: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b, *b]\n" +
+				"d: &d [*c, *c, *c, *c, *c, *c, *c, *c, *c, *c]\n" +
+				"e: &e [*d, *d, *d, *d, *d, *d, *d, *d, *d, *d]\n" +
+				"f: &f [*e, *e, *e, *e, *e, *e, *e, *e, *e, *e]\n" +
+				"g: [*f, *f, *f, *f, *f, *f, *f, *f, *f, *f]\n",
+			wantErr: "aliases expand to more than",
+		},
+		{
+			name:    "root is not a mapping",
+			data:    "- swagger\n",
+			wantErr: "root must be a mapping",
+		},
+		{
+			name:    "empty",
+			data:    "",
+			wantErr: "empty specification",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := decodeGenericSpec([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want map[string]interface{}
+			decoder := json.NewDecoder(strings.NewReader(tt.want))
+			decoder.UseNumber()
+			if err := decoder.Decode(&want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(raw, want) {
+				got, _ := json.Marshal(raw)
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectSpecVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    SpecVersion
+		wantErr string
+	}{
+		{name: "swagger json", data: `{"swagger": "2.0"}`, want: SpecVersion{FormatSwagger2, "2.0"}},
+		{name: "swagger yaml with an unquoted version", data: "swagger: 2.0\n", want: SpecVersion{FormatSwagger2, "2.0"}},
+		{name: "openapi 3.0 after a comment and a document marker", data: "# API\n---\ninfo: {title: t}\nopenapi: 3.0.3\n", want: SpecVersion{FormatOpenAPI30, "3.0.3"}},
+		{name: "openapi 3.0 without a patch version", data: "openapi: 3.0\n", want: SpecVersion{FormatOpenAPI30, "3.0"}},
+		{name: "openapi 3.1", data: `{"openapi": "3.1.0"}`, want: SpecVersion{FormatOpenAPI31, "3.1.0"}},
+		{name: "byte order mark", data: "\xef\xbb\xbfopenapi: 3.1.1\n", want: SpecVersion{FormatOpenAPI31, "3.1.1"}},
+		{name: "unsupported swagger version", data: "swagger: '1.2'\n", wantErr: `unsupported Swagger version "1.2"`},
+		{name: "unsupported openapi version", data: "openapi: 4.0.0\n", wantErr: `unsupported OpenAPI version "4.0.0"`},
+		{name: "both fields", data: "swagger: '2.0'\nopenapi: 3.0.0\n", wantErr: "both 'swagger' and 'openapi'"},
+		{name: "neither field", data: "info: {}\npaths: {}\n", wantErr: "no top-level 'swagger' or 'openapi' field (found: info, paths)"},
+		{name: "not a document", data: "{: [", wantErr: "neither valid JSON nor YAML"},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("spec%d", i))
+			writeFile(t, path, tt.data)
+			got, err := DetectSpecVersion(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// endpointOrder lists the endpoints of a specification as "METHOD path", each
+// followed by its cases as name/in, in the order the processor extracted them
+func endpointOrder(t *testing.T, p SpecProcessor, path string) []string {
+	t.Helper()
+	endpoints, err := p.ProcessFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, ep := range endpoints {
+		order = append(order, strings.ToUpper(ep.Method)+" "+ep.Endpoint)
+		for _, c := range ep.Cases {
+			order = append(order, "  "+c.ParamName+"/"+c.ParamIn)
+		}
+	}
+	return order
+}
+
+func TestEndpointOrdering(t *testing.T) {
+	const body = `{"type": "object", "properties": {"zeta": {"type": "string"}, "alpha": {"type": "string"}, "mid": {"type": "integer"}}}`
+	// operations declares the methods of /a out of order, with %s standing for
+	// how a parameter declares its type
+	const operations = `"delete": {"responses": {"204": {"description": "ok"}}},
+		"patch": {"responses": {"200": {"description": "ok"}}},
+		"get": {"parameters": [
+			{"name": "z", "in": "query", %[1]s},
+			{"name": "a", "in": "query", %[1]s},
+			{"name": "m", "in": "header", %[1]s}],
+			"responses": {"200": {"description": "ok"}}}`
+	want := []string{
+		"GET /a",
+		"  z/query",
+		"  a/query",
+		"  m/header",
+		"PATCH /a",
+		"DELETE /a",
+		"POST /b",
+		"  alpha/body",
+		"  mid/body",
+		"  zeta/body",
+		"PUT /c",
+	}
+
+	dir := t.TempDir()
+	specs := map[string]struct {
+		p    SpecProcessor
+		spec string
+	}{
+		"swagger 2.0": {&Swagger2Processor{}, `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {
+			"/c": {"put": {"responses": {"200": {"description": "ok"}}}},
+			"/a": {` + fmt.Sprintf(operations, `"type": "string"`) + `},
+			"/b": {"post": {"parameters": [{"name": "b", "in": "body", "schema": ` + body + `}], "responses": {"200": {"description": "ok"}}}}}}`},
+		"openapi 3.0": {&OpenAPI3Processor{}, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"}, "paths": {
+			"/c": {"put": {"responses": {"200": {"description": "ok"}}}},
+			"/a": {` + fmt.Sprintf(operations, `"schema": {"type": "string"}`) + `},
+			"/b": {"post": {"requestBody": {"content": {"application/json": {"schema": ` + body + `}}}, "responses": {"200": {"description": "ok"}}}}}}`},
+	}
+	for name, s := range specs {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(name, " ", "")+".json")
+			writeFile(t, path, s.spec)
+			// Maps iterate in a different order on every run, so repeat to catch leaks
+			for i := 0; i < 10; i++ {
+				if got := endpointOrder(t, s.p, path); !reflect.DeepEqual(got, want) {
+					t.Fatalf("run %d: got\n%s\nwant\n%s", i, strings.Join(got, "\n"), strings.Join(want, "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestMergePathLevelParameters(t *testing.T) {
+	// %[1]s stands for how a parameter declares its type
+	const params = `"/pets/{id}": {
+		"parameters": [
+			{"name": "id", "in": "path", "required": true, "description": "path level", %[1]s},
+			{"name": "tag", "in": "query", "description": "path level", %[1]s},
+			{"name": "trace", "in": "header", "description": "path level", %[1]s}],
+		"get": {"parameters": [
+			{"name": "tag", "in": "query", "required": true, "description": "operation level", %[1]s},
+			{"name": "trace", "in": "query", "description": "operation level", %[1]s}],
+			"responses": {"200": {"description": "ok"}}},
+		"delete": {"responses": {"204": {"description": "ok"}}}}`
+	want := map[string][]string{
+		"GET": {
+			"tag/query required operation level",
+			"trace/query operation level",
+			"id/path required path level",
+			"trace/header path level",
+		},
+		"DELETE": {
+			"id/path required path level",
+			"tag/query path level",
+			"trace/header path level",
+		},
+	}
+
+	dir := t.TempDir()
+	specs := map[string]struct {
+		p    SpecProcessor
+		spec string
+	}{
+		"swagger 2.0": {&Swagger2Processor{}, `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {` +
+			fmt.Sprintf(params, `"type": "string"`) + `}}`},
+		"openapi 3.0": {&OpenAPI3Processor{}, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"}, "paths": {` +
+			fmt.Sprintf(params, `"schema": {"type": "string"}`) + `}}`},
+	}
+	for name, s := range specs {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(name, " ", "")+".json")
+			writeFile(t, path, s.spec)
+			endpoints, err := s.p.ProcessFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string][]string{}
+			for _, ep := range endpoints {
+				method := strings.ToUpper(ep.Method)
+				for _, c := range ep.Cases {
+					required := ""
+					if c.Required {
+						required = " required"
+					}
+					got[method] = append(got[method], c.ParamName+"/"+c.ParamIn+required+" "+c.Description)
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestMediaTypeKinds(t *testing.T) {
+	tests := []struct {
+		mediaType         string
+		json, upload, bin bool
+	}{
+		{mediaType: "application/json", json: true},
+		{mediaType: "Application/JSON; charset=utf-8", json: true},
+		{mediaType: "application/vnd.api+json", json: true},
+		{mediaType: "application/merge-patch+json", json: true},
+		{mediaType: "application/xml"},
+		{mediaType: "application/atom+xml"},
+		{mediaType: "application/x-www-form-urlencoded"},
+		{mediaType: "text/plain"},
+		{mediaType: "multipart/form-data", upload: true},
+		{mediaType: "multipart/mixed", upload: true},
+		{mediaType: "application/octet-stream", upload: true, bin: true},
+		{mediaType: "image/png", upload: true, bin: true},
+		{mediaType: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mediaType, func(t *testing.T) {
+			if got := isJSONMediaType(tt.mediaType); got != tt.json {
+				t.Errorf("isJSONMediaType = %v, want %v", got, tt.json)
+			}
+			if got := isUploadMediaType(tt.mediaType); got != tt.upload {
+				t.Errorf("isUploadMediaType = %v, want %v", got, tt.upload)
+			}
+			if got := isBinaryMediaType(tt.mediaType); got != tt.bin {
+				t.Errorf("isBinaryMediaType = %v, want %v", got, tt.bin)
+			}
+		})
+	}
+}
+
+func TestOrderMediaTypes(t *testing.T) {
+	got := orderMediaTypes([]string{"application/xml", "application/vnd.api+json", "multipart/form-data", "application/json; charset=utf-8", "application/merge-patch+json"})
+	want := []string{"application/json; charset=utf-8", "application/vnd.api+json", "application/merge-patch+json", "application/xml", "multipart/form-data"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSortStatusCodes(t *testing.T) {
+	codes := []string{"default", "500", "4XX", "404", "201", "2XX", "400", "200", "5xx"}
+	sortStatusCodes(codes)
+	want := []string{"200", "201", "2XX", "400", "404", "4XX", "500", "5xx", "default"}
+	if !reflect.DeepEqual(codes, want) {
+		t.Errorf("got %q, want %q", codes, want)
+	}
+}
+
+func TestResponses(t *testing.T) {
+	const pet = `{"type": "object", "required": ["id", "owner", "tags"], "properties": {
+		"id": {"type": "integer"},
+		"nickname": {"type": "string"},
+		"owner": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "email": {"type": "string"}}},
+		"tags": {"type": "array", "items": {"type": "object", "required": ["label"], "properties": {"label": {"type": "string"}}}}}}`
+	want := []ResponseCase{
+		{StatusCode: "200", Description: "the pet", MediaTypes: []string{"application/json", "application/xml"},
+			RequiredFields: []string{"id", "owner", "owner.name", "tags", "tags[].label"}, Headers: []string{"X-Rate-Limit", "X-Request-Id"}},
+		{StatusCode: "201", Description: "a list", MediaTypes: []string{"application/json", "application/xml"},
+			RequiredFields: []string{"[].id", "[].owner", "[].owner.name", "[].tags", "[].tags[].label"}},
+		{StatusCode: "404", Description: "not found"},
+		{StatusCode: "default", Description: "error"},
+	}
+
+	dir := t.TempDir()
+	swagger := filepath.Join(dir, "swagger.json")
+	writeFile(t, swagger, `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "produces": ["application/xml", "application/json"],
+		"paths": {"/pets": {"get": {"responses": {
+			"404": {"description": "not found"},
+			"default": {"description": "error"},
+			"201": {"description": "a list", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+			"200": {"description": "the pet", "schema": {"$ref": "#/definitions/Pet"},
+				"headers": {"X-Request-Id": {"type": "string"}, "X-Rate-Limit": {"type": "integer"}}}}}}},
+		"definitions": {"Pet": `+pet+`}}`)
+	openapi := filepath.Join(dir, "openapi.json")
+	writeFile(t, openapi, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/pets": {"get": {"responses": {
+			"404": {"description": "not found"},
+			"default": {"description": "error"},
+			"201": {"description": "a list", "content": {
+				"application/xml": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}},
+				"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}},
+			"200": {"description": "the pet", "content": {
+				"application/xml": {"schema": {"type": "string"}},
+				"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}},
+				"headers": {"X-Request-Id": {"schema": {"type": "string"}}, "X-Rate-Limit": {"schema": {"type": "integer"}}}}}}}},
+		"components": {"schemas": {"Pet": `+pet+`}}}`)
+
+	for name, s := range map[string]struct {
+		p    SpecProcessor
+		path string
+	}{
+		"swagger 2.0": {&Swagger2Processor{}, swagger},
+		"openapi 3.0": {&OpenAPI3Processor{}, openapi},
+	} {
+		t.Run(name, func(t *testing.T) {
+			endpoints, err := s.p.ProcessFile(s.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(endpoints) != 1 {
+				t.Fatalf("got %d endpoints, want 1", len(endpoints))
+			}
+			// Compared as text, extraction leaves empty lists nil or empty alike
+			if got := endpoints[0].Responses; fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", want) {
+				t.Errorf("got %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSecurityRequirements(t *testing.T) {
+	// %[1]s holds the security definitions of the format
+	const doc = `"security": [{"key": []}], %[1]s,
+		"paths": {
+			"/inherited": {"get": {"responses": {"200": {"description": "ok"}}}},
+			"/anonymous": {"get": {"security": [], "responses": {"200": {"description": "ok"}}}},
+			"/optional": {"get": {"security": [{}, {"oauth": ["read", "write"]}], "responses": {"200": {"description": "ok"}}}},
+			"/alternatives": {"get": {"security": [{"basic": []}, {"oauth": ["read"], "key": []}], "responses": {"200": {"description": "ok"}}}},
+			"/undeclared": {"get": {"security": [{"missing": []}], "responses": {"200": {"description": "ok"}}}}}`
+	// Each requirement as name:type:scheme:in:param:scopes of its schemes
+	want := map[string][]string{
+		"/inherited":    {"key:apiKey::header:X-API-Key:[]"},
+		"/anonymous":    {"anonymous"},
+		"/optional":     {"anonymous", "oauth:oauth2::::[read write]"},
+		"/alternatives": {"basic:http:basic:::[]", "key:apiKey::header:X-API-Key:[] + oauth:oauth2::::[read]"},
+		"/undeclared":   nil,
+	}
+
+	dir := t.TempDir()
+	swagger := filepath.Join(dir, "swagger.json")
+	writeFile(t, swagger, `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, `+fmt.Sprintf(doc, `"securityDefinitions": {
+		"key": {"type": "apiKey", "in": "header", "name": "X-API-Key"},
+		"basic": {"type": "basic"},
+		"oauth": {"type": "oauth2", "flow": "implicit", "authorizationUrl": "https://example.com/auth", "scopes": {"read": "", "write": ""}}}`)+`}`)
+	openapi := filepath.Join(dir, "openapi.json")
+	writeFile(t, openapi, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"}, `+fmt.Sprintf(doc, `"components": {"securitySchemes": {
+		"key": {"type": "apiKey", "in": "header", "name": "X-API-Key"},
+		"basic": {"type": "http", "scheme": "basic"},
+		"oauth": {"type": "oauth2", "flows": {"implicit": {"authorizationUrl": "https://example.com/auth", "scopes": {"read": "", "write": ""}}}}}}`)+`}`)
+
+	for name, s := range map[string]struct {
+		p    SpecProcessor
+		path string
+	}{
+		"swagger 2.0": {&Swagger2Processor{}, swagger},
+		"openapi 3.0": {&OpenAPI3Processor{}, openapi},
+	} {
+		t.Run(name, func(t *testing.T) {
+			endpoints, err := s.p.ProcessFile(s.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string][]string{}
+			for _, ep := range endpoints {
+				var lines []string
+				if ep.AnonymousAllowed {
+					lines = append(lines, "anonymous")
+				}
+				for _, req := range ep.Security {
+					var schemes []string
+					for _, sc := range req.Schemes {
+						schemes = append(schemes, fmt.Sprintf("%s:%s:%s:%s:%s:%v", sc.Name, sc.Type, sc.Scheme, sc.In, sc.ParamName, sc.Scopes))
+					}
+					lines = append(lines, strings.Join(schemes, " + "))
+				}
+				got[ep.Endpoint] = lines
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestServerBasePath(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.example.com", ""},
+		{"https://api.example.com/", ""},
+		{"https://api.example.com/v1", "/v1"},
+		{"https://api.example.com/v1/", "/v1"},
+		{"http://localhost:8080/api/v2", "/api/v2"},
+		{"/v1", "/v1"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := serverBasePath(tt.url); got != tt.want {
+			t.Errorf("serverBasePath(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
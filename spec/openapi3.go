@@ -1,11 +1,16 @@
 package processor
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/getkin/kin-openapi/openapi3"
 )
 
 // OpenAPI3Processor handles OpenAPI 3.0 specifications
-type OpenAPI3Processor struct{}
+type OpenAPI3Processor struct {
+	MaxDepth int // nesting limit for request body fields, DefaultMaxBodyDepth when 0
+}
 
 // ProcessFile loads and processes an OpenAPI 3.0 specification file
 func (p *OpenAPI3Processor) ProcessFile(filename string) ([]EndpointCases, error) {
@@ -14,7 +19,7 @@ func (p *OpenAPI3Processor) ProcessFile(filename string) ([]EndpointCases, error
 		return nil, err
 	}
 
-	return extractEndpointsOpenAPI3(doc), nil
+	return extractEndpointsOpenAPI3(doc, p.MaxDepth), nil
 }
 
 // loadOpenAPI3Spec loads an OpenAPI 3.0 specification
@@ -34,14 +39,24 @@ func loadOpenAPI3Spec(path string) (*openapi3.T, error) {
 }
 
 // extractEndpointsOpenAPI3 extracts endpoints from OpenAPI 3.0 specification
-func extractEndpointsOpenAPI3(doc *openapi3.T) []EndpointCases {
+func extractEndpointsOpenAPI3(doc *openapi3.T, maxDepth int) []EndpointCases {
 	results := []EndpointCases{}
 
-	for path, pathItem := range doc.Paths {
-		// Get all operations for this path
-		operations := pathItem.Operations()
+	var components openapi3.Schemas
+	if doc.Components != nil {
+		components = doc.Components.Schemas
+	}
+
+	// Paths are sorted and methods follow canonicalMethods so output is reproducible
+	for _, path := range sortedKeys(doc.Paths) {
+		pathItem := doc.Paths[path]
+
+		for _, method := range canonicalMethods {
+			operation := pathItem.GetOperation(method)
+			if operation == nil {
+				continue
+			}
 
-		for method, operation := range operations {
 			ec := EndpointCases{
 				Endpoint: path,
 				Method:   method,
@@ -49,7 +64,7 @@ func extractEndpointsOpenAPI3(doc *openapi3.T) []EndpointCases {
 			}
 
 			// 1. Extract parameters (query, path, header, cookie)
-			for _, paramRef := range operation.Parameters {
+			for _, paramRef := range mergeParametersOpenAPI3(pathItem.Parameters, operation.Parameters) {
 				if paramRef.Value == nil {
 					continue
 				}
@@ -62,23 +77,49 @@ func extractEndpointsOpenAPI3(doc *openapi3.T) []EndpointCases {
 					Description: p.Description,
 					EnumValues:  enumFromSchema(p.Schema),
 					DataType:    extractDataTypeFromOpenAPI3Schema(p.Schema),
+					Nullable:    p.Schema != nil && p.Schema.Value != nil && p.Schema.Value.Nullable,
+				}
+				pc.Extensions = vendorExtensions(p.Extensions)
+				if sm, err := p.SerializationMethod(); err == nil && p.Schema != nil {
+					pc.Style, pc.Explode, pc.AllowReserved = sm.Style, sm.Explode, p.AllowReserved
 				}
+				if p.Schema != nil && p.Schema.Value != nil {
+					pc.Constraints = constraintsFromOpenAPI3Schema(mergeAllOfOpenAPI3(p.Schema.Value))
+					pc.Extensions = vendorExtensions(p.Extensions, p.Schema.Value.Extensions)
+				}
+				if pc.Constraints.Example == nil {
+					pc.Constraints.Example = p.Example
+				}
+				pc.Constraints.Deprecated = pc.Constraints.Deprecated || p.Deprecated
 
 				ec.Cases = append(ec.Cases, pc)
 			}
 
-			// 2. Extract request body (JSON schema)
-			if operation.RequestBody != nil &&
-				operation.RequestBody.Value != nil &&
-				operation.RequestBody.Value.Content["application/json"] != nil {
+			// 2. Extract request body, once per declared media type
+			if operation.RequestBody != nil && operation.RequestBody.Value != nil {
+				body := operation.RequestBody.Value
+				ec.MediaTypes = orderMediaTypes(sortedKeys(body.Content))
 
-				bodySchema := operation.RequestBody.Value.Content["application/json"].Schema
-				if bodySchema != nil {
-					bodyCases := extractRequestBodyCasesOpenAPI3(path, method, bodySchema)
+				for _, mediaType := range ec.MediaTypes {
+					if body.Content[mediaType] == nil || body.Content[mediaType].Schema == nil {
+						continue
+					}
+					bodySchema := body.Content[mediaType].Schema
+					bodyCases, variants := extractRequestBodyCasesOpenAPI3(path, method, mediaType, body.Required, bodySchema, body.Content[mediaType].Encoding, components, bodyDepth(maxDepth))
 					ec.Cases = append(ec.Cases, bodyCases...)
+					ec.Variants = append(ec.Variants, variants...)
 				}
 			}
 
+			// 3. Extract documented responses
+			ec.Responses = extractResponsesOpenAPI3(operation.Responses, bodyDepth(maxDepth))
+
+			// 4. Extract security requirements
+			ec.Security, ec.AnonymousAllowed = securityOpenAPI3(doc, operation)
+
+			// 5. Resolve the servers the endpoint is served under
+			ec.Servers = serversOpenAPI3(doc, pathItem, operation)
+
 			results = append(results, ec)
 		}
 	}
@@ -86,40 +127,589 @@ func extractEndpointsOpenAPI3(doc *openapi3.T) []EndpointCases {
 	return results
 }
 
-// extractRequestBodyCasesOpenAPI3 extracts request body cases from OpenAPI 3.0 schema
-func extractRequestBodyCasesOpenAPI3(path, method string, schemaRef *openapi3.SchemaRef) []ParameterCase {
+// serversOpenAPI3 resolves the servers of an operation: its own, else its
+// path's, else the document's. Each server is expanded into one entry per
+// combination of the enum values of its variables, defaults first.
+func serversOpenAPI3(doc *openapi3.T, pathItem *openapi3.PathItem, operation *openapi3.Operation) []Server {
+	servers := doc.Servers
+	if len(pathItem.Servers) > 0 {
+		servers = pathItem.Servers
+	}
+	if operation.Servers != nil && len(*operation.Servers) > 0 {
+		servers = *operation.Servers
+	}
+
+	var out []Server
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+		combinations := []map[string]string{{}}
+		for _, name := range sortedKeys(server.Variables) {
+			variable := server.Variables[name]
+			if variable == nil {
+				continue
+			}
+			values := []string{variable.Default}
+			for _, v := range variable.Enum {
+				if v != variable.Default {
+					values = append(values, v)
+				}
+			}
+
+			var expanded []map[string]string
+			for _, combination := range combinations {
+				for _, v := range values {
+					next := map[string]string{name: v}
+					for k, kv := range combination {
+						next[k] = kv
+					}
+					expanded = append(expanded, next)
+				}
+			}
+			combinations = expanded
+		}
+
+		for _, variables := range combinations {
+			resolved := server.URL
+			for name, value := range variables {
+				resolved = strings.ReplaceAll(resolved, "{"+name+"}", value)
+			}
+			resolved = strings.TrimSuffix(resolved, "/")
+			s := Server{URL: resolved, BasePath: serverBasePath(resolved)}
+			if len(variables) > 0 {
+				s.Variables = variables
+			}
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// securityOpenAPI3 resolves the security requirements of an operation,
+// inheriting the document's when the operation declares none. Requirements
+// naming an undeclared scheme leave that scheme out.
+func securityOpenAPI3(doc *openapi3.T, operation *openapi3.Operation) ([]SecurityRequirement, bool) {
+	requirements := doc.Security
+	anonymous := false
+	if operation.Security != nil {
+		requirements = *operation.Security
+		anonymous = len(requirements) == 0
+	}
+
+	var schemes openapi3.SecuritySchemes
+	if doc.Components != nil {
+		schemes = doc.Components.SecuritySchemes
+	}
+
+	var out []SecurityRequirement
+	for _, requirement := range requirements {
+		if len(requirement) == 0 {
+			anonymous = true
+			continue
+		}
+		var req SecurityRequirement
+		for _, name := range sortedKeys(requirement) {
+			ref := schemes[name]
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			req.Schemes = append(req.Schemes, SecurityScheme{
+				Name:      name,
+				Type:      ref.Value.Type,
+				Scheme:    strings.ToLower(ref.Value.Scheme),
+				In:        ref.Value.In,
+				ParamName: ref.Value.Name,
+				Scopes:    requirement[name],
+			})
+		}
+		if len(req.Schemes) > 0 {
+			out = append(out, req)
+		}
+	}
+
+	return out, anonymous
+}
+
+// extractResponsesOpenAPI3 describes every documented response of an
+// operation, with the required fields of its preferred media type
+func extractResponsesOpenAPI3(responses openapi3.Responses, maxDepth int) []ResponseCase {
+	codes := sortedKeys(responses)
+	sortStatusCodes(codes)
+
+	var out []ResponseCase
+	for _, code := range codes {
+		ref := responses[code]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		resp := ref.Value
+
+		rc := ResponseCase{
+			StatusCode: code,
+			MediaTypes: orderMediaTypes(sortedKeys(resp.Content)),
+			Headers:    sortedKeys(resp.Headers),
+		}
+		if resp.Description != nil {
+			rc.Description = *resp.Description
+		}
+		if len(rc.MediaTypes) > 0 {
+			if mt := resp.Content[rc.MediaTypes[0]]; mt != nil && mt.Schema != nil && mt.Schema.Value != nil {
+				rc.RequiredFields = requiredFieldsOpenAPI3(mt.Schema.Value, "", maxDepth, map[*openapi3.Schema]bool{})
+			}
+		}
+		out = append(out, rc)
+	}
+
+	return out
+}
+
+// requiredFieldsOpenAPI3 lists the required properties of a response schema
+// and, below them, those of nested objects and array items (named items[]),
+// up to maxDepth levels
+func requiredFieldsOpenAPI3(schema *openapi3.Schema, prefix string, maxDepth int, visited map[*openapi3.Schema]bool) []string {
+	if maxDepth <= 0 || visited[schema] {
+		return nil
+	}
+	visited[schema] = true
+	defer delete(visited, schema)
+
+	merged := mergeAllOfOpenAPI3(schema)
+	if merged.Type == "array" && merged.Items != nil && merged.Items.Value != nil {
+		return requiredFieldsOpenAPI3(merged.Items.Value, strings.TrimSuffix(prefix, ".")+"[].", maxDepth-1, visited)
+	}
+
+	var fields []string
+	for _, name := range sortedKeys(merged.Properties) {
+		p := merged.Properties[name]
+		if !contains(merged.Required, name) || p == nil || p.Value == nil {
+			continue
+		}
+		fields = append(fields, prefix+name)
+		fields = append(fields, requiredFieldsOpenAPI3(p.Value, prefix+name+".", maxDepth-1, visited)...)
+	}
+	return fields
+}
+
+// mergeParametersOpenAPI3 combines path-level and operation-level parameters.
+// Operation parameters come first in declaration order, followed by the path
+// parameters the operation does not override by name and location.
+func mergeParametersOpenAPI3(pathParams, opParams openapi3.Parameters) openapi3.Parameters {
+	merged := openapi3.Parameters{}
+	seen := map[string]bool{}
+
+	for _, params := range []openapi3.Parameters{opParams, pathParams} {
+		for _, paramRef := range params {
+			if paramRef == nil || paramRef.Value == nil {
+				continue
+			}
+			key := parameterKey(paramRef.Value.Name, paramRef.Value.In)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, paramRef)
+		}
+	}
+
+	return merged
+}
+
+// extractRequestBodyCasesOpenAPI3 extracts request body cases from OpenAPI 3.0 schema,
+// descending into nested objects and array items up to maxDepth levels. A body
+// without properties (e.g. an octet-stream upload) yields a single case named body.
+// oneOf/anyOf compositions are returned as variant groups, with the fields of
+// each variant tagged with its name. The multipart encoding gives the content
+// types accepted for file parts.
+func extractRequestBodyCasesOpenAPI3(path, method, mediaType string, required bool, schemaRef *openapi3.SchemaRef, encoding map[string]*openapi3.Encoding, components openapi3.Schemas, maxDepth int) ([]ParameterCase, []VariantGroup) {
 	out := []ParameterCase{}
 	if schemaRef.Value == nil {
+		return out, nil
+	}
+
+	w := &bodyWalkerOpenAPI3{
+		mediaType:  mediaType,
+		encoding:   encoding,
+		maxDepth:   maxDepth,
+		components: components,
+		visited:    map[*openapi3.Schema]bool{},
+	}
+
+	root := schemaRef.Value
+	merged := mergeAllOfOpenAPI3(root)
+	if kind, variants := w.variants(root, merged); kind != "" {
+		w.visited[root] = true
+		return w.composition(out, "", merged, kind, variants, 1), w.groups
+	}
+
+	if len(merged.Properties) == 0 {
+		out = append(out, w.fieldCase("body", required, root))
+		return w.nested(out, "body", root, 1), w.groups
+	}
+
+	w.visited[root] = true
+	return w.properties(out, "", merged, 1), w.groups
+}
+
+// bodyWalkerOpenAPI3 walks the schema of one request body media type
+type bodyWalkerOpenAPI3 struct {
+	mediaType  string
+	encoding   map[string]*openapi3.Encoding // multipart encoding by property name
+	maxDepth   int
+	components openapi3.Schemas          // searched for schemas extending a discriminator base
+	visited    map[*openapi3.Schema]bool // schemas on the current path, for cycle protection
+	variant    string                    // variant currently being walked
+	groups     []VariantGroup
+}
+
+// fieldCase builds the case for a single body field
+func (w *bodyWalkerOpenAPI3) fieldCase(fieldPath string, required bool, schema *openapi3.Schema) ParameterCase {
+	merged := mergeAllOfOpenAPI3(schema)
+	dataType := extractDataTypeFromOpenAPI3Schema(&openapi3.SchemaRef{Value: merged})
+	var fileTypes []string
+	if isUploadMediaType(w.mediaType) {
+		dataType = uploadDataTypeOpenAPI3(merged, dataType)
+		switch {
+		case dataType != "file" && dataType != "array[file]":
+		case isBinaryMediaType(w.mediaType):
+			fileTypes = splitContentTypes(w.mediaType)
+		case w.encoding[fieldPath] != nil:
+			fileTypes = splitContentTypes(w.encoding[fieldPath].ContentType)
+		}
+	}
+
+	return ParameterCase{
+		ParamName:   fieldPath,
+		ParamIn:     "body",
+		Required:    required,
+		EnumValues:  merged.Enum,
+		Description: merged.Description,
+		DataType:    dataType,
+		Nullable:    merged.Nullable,
+		MediaType:   w.mediaType,
+		FileTypes:   fileTypes,
+		Variant:     w.variant,
+		Constraints: constraintsFromOpenAPI3Schema(merged),
+		Extensions:  vendorExtensions(schema.Extensions, merged.Extensions),
+	}
+}
+
+// properties adds a case for each property of an object schema, followed by
+// the cases of its nested fields. A nested field is required when its parent
+// object lists it as required.
+func (w *bodyWalkerOpenAPI3) properties(out []ParameterCase, prefix string, schema *openapi3.Schema, depth int) []ParameterCase {
+	for _, name := range sortedKeys(schema.Properties) {
+		s := schema.Properties[name]
+		if s == nil || s.Value == nil {
+			continue
+		}
+
+		fieldPath := prefix + name
+		out = append(out, w.fieldCase(fieldPath, contains(schema.Required, name), s.Value))
+		out = w.nested(out, fieldPath, s.Value, depth)
+	}
+
+	return out
+}
+
+// nested descends into the properties of an object field, the variants of a
+// composed field or the item schema of an array field (named items[]).
+// Schemas already on the current path are skipped so self-referencing schemas
+// terminate.
+func (w *bodyWalkerOpenAPI3) nested(out []ParameterCase, fieldPath string, schema *openapi3.Schema, depth int) []ParameterCase {
+	if depth >= w.maxDepth || w.visited[schema] {
 		return out
 	}
+	w.visited[schema] = true
+	defer delete(w.visited, schema)
+
+	merged := mergeAllOfOpenAPI3(schema)
+	if kind, variants := w.variants(schema, merged); kind != "" {
+		return w.composition(out, fieldPath, merged, kind, variants, depth+1)
+	}
+
+	switch {
+	case merged.Type == "array" && merged.Items != nil && merged.Items.Value != nil:
+		return w.nested(out, fieldPath+"[]", merged.Items.Value, depth+1)
+	case len(merged.Properties) > 0:
+		return w.properties(out, fieldPath+".", merged, depth+1)
+	}
+
+	return out
+}
+
+// variants returns the alternatives of a composed schema: its oneOf or anyOf
+// members when at least one of them is an object, or, for a schema with a
+// discriminator, the component schemas extending it through allOf
+func (w *bodyWalkerOpenAPI3) variants(original, merged *openapi3.Schema) (string, openapi3.SchemaRefs) {
+	for _, kind := range []string{"oneOf", "anyOf"} {
+		refs := merged.OneOf
+		if kind == "anyOf" {
+			refs = merged.AnyOf
+		}
+		for _, v := range refs {
+			if v != nil && v.Value != nil && len(mergeAllOfOpenAPI3(v.Value).Properties) > 0 {
+				return kind, refs
+			}
+		}
+	}
+
+	if merged.Discriminator == nil {
+		return "", nil
+	}
 
-	for name, s := range schemaRef.Value.Properties {
-		pc := ParameterCase{
-			ParamName:   name,
-			ParamIn:     "body",
-			Required:    contains(schemaRef.Value.Required, name),
-			EnumValues:  s.Value.Enum,
-			Description: s.Value.Description,
-			DataType:    extractDataTypeFromOpenAPI3Schema(s),
+	var subtypes openapi3.SchemaRefs
+	for _, name := range sortedKeys(w.components) {
+		sub := w.components[name]
+		if sub == nil || sub.Value == nil {
+			continue
+		}
+		for _, member := range sub.Value.AllOf {
+			if member != nil && member.Value == original {
+				subtypes = append(subtypes, &openapi3.SchemaRef{Ref: "#/components/schemas/" + name, Value: sub.Value})
+				break
+			}
+		}
+	}
+	if len(subtypes) == 0 {
+		return "", nil
+	}
+	return "oneOf", subtypes
+}
+
+// composition records a variant group and walks the fields of each variant,
+// together with the properties declared next to the oneOf/anyOf, under the
+// variant's name
+func (w *bodyWalkerOpenAPI3) composition(out []ParameterCase, fieldPath string, merged *openapi3.Schema, kind string, variants openapi3.SchemaRefs, depth int) []ParameterCase {
+	group := VariantGroup{
+		Field:     fieldPath,
+		Variant:   w.variant,
+		Kind:      kind,
+		MediaType: w.mediaType,
+	}
+	if merged.Discriminator != nil {
+		group.Discriminator = merged.Discriminator.PropertyName
+	}
+
+	// Record the group before walking so nested groups follow it
+	idx := len(w.groups)
+	w.groups = append(w.groups, group)
+
+	prefix := ""
+	if fieldPath != "" {
+		prefix = fieldPath + "."
+	}
+
+	parent := w.variant
+	defer func() { w.variant = parent }()
+
+	for i, v := range variants {
+		if v == nil || v.Value == nil || w.visited[v.Value] {
+			continue
+		}
+
+		name, value := variantNameOpenAPI3(v, i, merged.Discriminator)
+		w.groups[idx].Variants = append(w.groups[idx].Variants, Variant{Name: name, DiscriminatorValue: value})
+
+		w.variant = joinVariant(parent, name)
+		w.visited[v.Value] = true
+		start := len(out)
+		out = w.properties(out, prefix, combinePropertiesOpenAPI3(merged, mergeAllOfOpenAPI3(v.Value)), depth)
+		delete(w.visited, v.Value)
+		if group.Discriminator != "" {
+			pinDiscriminator(out[start:], prefix+group.Discriminator, w.variant, discriminatorValuesOpenAPI3(v, merged.Discriminator, value))
 		}
-		out = append(out, pc)
 	}
 
 	return out
 }
 
+// variantNameOpenAPI3 names a variant after its discriminator mapping value,
+// the schema it references, its title, or its position. The second result is
+// the discriminator value selecting the variant.
+func variantNameOpenAPI3(v *openapi3.SchemaRef, i int, d *openapi3.Discriminator) (string, string) {
+	refName := ""
+	if v.Ref != "" {
+		refName = v.Ref[strings.LastIndex(v.Ref, "/")+1:]
+	}
+
+	if d != nil {
+		for _, value := range sortedKeys(d.Mapping) {
+			if target := d.Mapping[value]; target == v.Ref || (refName != "" && target == refName) {
+				return value, value
+			}
+		}
+		// Without a mapping the discriminator value is the schema name
+		if refName != "" {
+			return refName, refName
+		}
+	}
+
+	switch {
+	case refName != "":
+		return refName, ""
+	case v.Value.Title != "":
+		return v.Value.Title, ""
+	}
+	return fmt.Sprintf("variant%d", i+1), ""
+}
+
+// discriminatorValuesOpenAPI3 lists the discriminator values selecting a
+// variant: every mapping entry targeting it, or else its own value
+func discriminatorValuesOpenAPI3(v *openapi3.SchemaRef, d *openapi3.Discriminator, value string) []interface{} {
+	refName := v.Ref[strings.LastIndex(v.Ref, "/")+1:]
+	var values []interface{}
+	for _, key := range sortedKeys(d.Mapping) {
+		if target := d.Mapping[key]; target == v.Ref || (refName != "" && target == refName) {
+			values = append(values, key)
+		}
+	}
+	if len(values) == 0 {
+		values = append(values, value)
+	}
+	return values
+}
+
+// combinePropertiesOpenAPI3 adds the properties declared next to a oneOf/anyOf
+// to those of one of its variants
+func combinePropertiesOpenAPI3(parent, variant *openapi3.Schema) *openapi3.Schema {
+	combined := *variant
+	combined.Properties = openapi3.Schemas{}
+	for name, p := range variant.Properties {
+		combined.Properties[name] = p
+	}
+	combined.Required = append([]string{}, variant.Required...)
+
+	for name, p := range parent.Properties {
+		if _, exists := combined.Properties[name]; !exists {
+			combined.Properties[name] = p
+		}
+	}
+	for _, name := range parent.Required {
+		if !contains(combined.Required, name) {
+			combined.Required = append(combined.Required, name)
+		}
+	}
+
+	return &combined
+}
+
+// mergeAllOfOpenAPI3 folds the members of an allOf into a single schema:
+// properties and required lists are combined, and type, format, items, enum,
+// nested oneOf/anyOf and the validation keywords are taken from the first
+// member declaring them. Properties of the schema itself win over those of
+// its members. Schemas without allOf are returned unchanged.
+func mergeAllOfOpenAPI3(schema *openapi3.Schema) *openapi3.Schema {
+	return mergeAllOfOpenAPI3Stack(schema, map[*openapi3.Schema]bool{})
+}
+
+func mergeAllOfOpenAPI3Stack(schema *openapi3.Schema, stack map[*openapi3.Schema]bool) *openapi3.Schema {
+	if len(schema.AllOf) == 0 || stack[schema] {
+		return schema
+	}
+	stack[schema] = true
+	defer delete(stack, schema)
+
+	merged := *schema
+	merged.AllOf = nil
+	merged.Properties = openapi3.Schemas{}
+	for name, p := range schema.Properties {
+		merged.Properties[name] = p
+	}
+	merged.Required = append([]string{}, schema.Required...)
+
+	for _, member := range schema.AllOf {
+		if member == nil || member.Value == nil {
+			continue
+		}
+		m := mergeAllOfOpenAPI3Stack(member.Value, stack)
+
+		for name, p := range m.Properties {
+			if _, exists := merged.Properties[name]; !exists {
+				merged.Properties[name] = p
+			}
+		}
+		for _, name := range m.Required {
+			if !contains(merged.Required, name) {
+				merged.Required = append(merged.Required, name)
+			}
+		}
+		if merged.Type == "" {
+			merged.Type = m.Type
+		}
+		if merged.Format == "" {
+			merged.Format = m.Format
+		}
+		if merged.Items == nil {
+			merged.Items = m.Items
+		}
+		if len(merged.Enum) == 0 {
+			merged.Enum = m.Enum
+		}
+		if merged.Description == "" {
+			merged.Description = m.Description
+		}
+		if len(merged.OneOf) == 0 {
+			merged.OneOf = m.OneOf
+		}
+		if len(merged.AnyOf) == 0 {
+			merged.AnyOf = m.AnyOf
+		}
+		mergeConstraintsOpenAPI3(&merged, m)
+	}
+
+	if merged.Type == "" && len(merged.Properties) > 0 {
+		merged.Type = "object"
+	}
+
+	return &merged
+}
+
+// uploadDataTypeOpenAPI3 reports binary strings as file (and arrays of them as
+// array[file]) so multipart and octet-stream bodies get upload cases
+func uploadDataTypeOpenAPI3(schema *openapi3.Schema, dataType string) string {
+	if schema.Type == "array" && schema.Items != nil && schema.Items.Value != nil {
+		if isBinaryOpenAPI3(schema.Items.Value) {
+			return "array[file]"
+		}
+		return dataType
+	}
+	if isBinaryOpenAPI3(schema) {
+		return "file"
+	}
+	return dataType
+}
+
+// isBinaryOpenAPI3 reports whether a schema describes raw file content
+func isBinaryOpenAPI3(schema *openapi3.Schema) bool {
+	return schema.Type == "string" && schema.Format == "binary"
+}
+
 // extractDataTypeFromOpenAPI3Schema extracts the data type from OpenAPI 3.0 schema
 func extractDataTypeFromOpenAPI3Schema(schemaRef *openapi3.SchemaRef) string {
+	return dataTypeOpenAPI3(schemaRef, map[*openapi3.Schema]bool{})
+}
+
+// dataTypeOpenAPI3 extracts the data type of a schema, seen holding the
+// schemas being typed. A schema found again inside itself, such as an array
+// whose items hold the array again, is reported as object, so recursive
+// arrays stop at types like array[array[object]].
+func dataTypeOpenAPI3(schemaRef *openapi3.SchemaRef, seen map[*openapi3.Schema]bool) string {
 	if schemaRef == nil || schemaRef.Value == nil {
 		return "string" // default to string if no schema
 	}
+	if seen[schemaRef.Value] {
+		return "object"
+	}
 
-	schema := schemaRef.Value
+	schema := mergeAllOfOpenAPI3Stack(schemaRef.Value, seen)
+	seen[schemaRef.Value] = true
+	defer delete(seen, schemaRef.Value)
 
 	// Handle array types
 	if schema.Type == "array" {
 		if schema.Items != nil && schema.Items.Value != nil {
-			return "array[" + extractDataTypeFromOpenAPI3Schema(schema.Items) + "]"
+			return "array[" + dataTypeOpenAPI3(schema.Items, seen) + "]"
 		}
 		return "array"
 	}
@@ -134,9 +724,129 @@ func extractDataTypeFromOpenAPI3Schema(schemaRef *openapi3.SchemaRef) string {
 		return schema.Type
 	}
 
+	// Handle unions of primitive types (e.g. OpenAPI 3.1 `type: [integer, string]`)
+	if union := primitiveUnion(schema.OneOf, seen); union != "" {
+		return union
+	}
+	if union := primitiveUnion(schema.AnyOf, seen); union != "" {
+		return union
+	}
+
+	// Untyped schemas with properties or object variants
+	if len(schema.Properties) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 {
+		return "object"
+	}
+
 	return "string" // default fallback
 }
 
+// primitiveUnion joins the types of the variants as integer|string when every
+// variant is a non-object type, and returns "" otherwise
+func primitiveUnion(variants openapi3.SchemaRefs, seen map[*openapi3.Schema]bool) string {
+	if len(variants) == 0 {
+		return ""
+	}
+
+	var types []string
+	for _, v := range variants {
+		if v == nil || v.Value == nil || v.Value.Type == "" || v.Value.Type == "object" {
+			return ""
+		}
+		dataType := dataTypeOpenAPI3(v, seen)
+		if !contains(types, dataType) {
+			types = append(types, dataType)
+		}
+	}
+
+	return strings.Join(types, "|")
+}
+
+// mergeConstraintsOpenAPI3 copies the validation keywords of an allOf member
+// that the merged schema does not declare itself
+func mergeConstraintsOpenAPI3(merged, m *openapi3.Schema) {
+	if merged.Min == nil {
+		merged.Min, merged.ExclusiveMin = m.Min, m.ExclusiveMin
+	}
+	if merged.Max == nil {
+		merged.Max, merged.ExclusiveMax = m.Max, m.ExclusiveMax
+	}
+	if merged.MultipleOf == nil {
+		merged.MultipleOf = m.MultipleOf
+	}
+	if merged.MinLength == 0 {
+		merged.MinLength = m.MinLength
+	}
+	if merged.MaxLength == nil {
+		merged.MaxLength = m.MaxLength
+	}
+	if merged.Pattern == "" {
+		merged.Pattern = m.Pattern
+	}
+	if merged.MinItems == 0 {
+		merged.MinItems = m.MinItems
+	}
+	if merged.MaxItems == nil {
+		merged.MaxItems = m.MaxItems
+	}
+	if merged.Default == nil {
+		merged.Default = m.Default
+	}
+	if merged.Example == nil {
+		merged.Example = m.Example
+	}
+	merged.UniqueItems = merged.UniqueItems || m.UniqueItems
+	merged.Nullable = merged.Nullable || m.Nullable
+	merged.ReadOnly = merged.ReadOnly || m.ReadOnly
+	merged.WriteOnly = merged.WriteOnly || m.WriteOnly
+	merged.Deprecated = merged.Deprecated || m.Deprecated
+}
+
+// constraintsFromOpenAPI3Schema collects the validation keywords of a schema
+func constraintsFromOpenAPI3Schema(schema *openapi3.Schema) Constraints {
+	return constraintsOpenAPI3(schema, map[*openapi3.Schema]bool{schema: true})
+}
+
+// constraintsOpenAPI3 collects the validation keywords of a schema, seen
+// holding the schemas being walked. Items found again inside themselves get
+// no constraints of their own.
+func constraintsOpenAPI3(schema *openapi3.Schema, seen map[*openapi3.Schema]bool) Constraints {
+	c := Constraints{
+		Minimum:          schema.Min,
+		Maximum:          schema.Max,
+		ExclusiveMinimum: schema.ExclusiveMin,
+		ExclusiveMaximum: schema.ExclusiveMax,
+		MultipleOf:       schema.MultipleOf,
+		MinLength:        nonZero(schema.MinLength),
+		MaxLength:        uint64Ptr(schema.MaxLength),
+		Pattern:          schema.Pattern,
+		Format:           schema.Format,
+		MinItems:         nonZero(schema.MinItems),
+		MaxItems:         uint64Ptr(schema.MaxItems),
+		UniqueItems:      schema.UniqueItems,
+		Default:          schema.Default,
+		Example:          schema.Example,
+		ReadOnly:         schema.ReadOnly,
+		WriteOnly:        schema.WriteOnly,
+		Deprecated:       schema.Deprecated,
+	}
+	if items := schema.Items; items != nil && items.Value != nil && !seen[items.Value] {
+		merged := mergeAllOfOpenAPI3Stack(items.Value, seen)
+		seen[items.Value] = true
+		ic := constraintsOpenAPI3(merged, seen)
+		delete(seen, items.Value)
+		c.Items = &ic
+	}
+	if len(schema.Properties) > 0 {
+		c.Properties = map[string]string{}
+		for name, p := range schema.Properties {
+			c.Properties[name] = dataTypeOpenAPI3(p, seen)
+		}
+	}
+	c.Required = schema.Required
+	c.AdditionalProperties = schema.AdditionalProperties.Has
+	return c
+}
+
 // safely extract enum values
 func enumFromSchema(ref *openapi3.SchemaRef) []interface{} {
 	if ref == nil || ref.Value == nil {
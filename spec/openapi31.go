@@ -0,0 +1,593 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OpenAPI31Processor handles OpenAPI 3.1 specifications.
+//
+// The document is rewritten into its OpenAPI 3.0 equivalent (type arrays become
+// `nullable` or a `oneOf` of single types, `const` becomes a one-value enum,
+// `$defs` are hoisted into components, ...) and then extracted exactly like 3.0.
+// Schemas in externally referenced files are loaded as-is and must therefore
+// already be 3.0 compatible.
+type OpenAPI31Processor struct {
+	MaxDepth int // nesting limit for request body fields, DefaultMaxBodyDepth when 0
+}
+
+// ProcessFile loads and processes an OpenAPI 3.1 specification file
+func (p *OpenAPI31Processor) ProcessFile(filename string) ([]EndpointCases, error) {
+	doc, err := loadOpenAPI31Spec(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return extractEndpointsOpenAPI3(doc, p.MaxDepth), nil
+}
+
+// loadOpenAPI31Spec loads an OpenAPI 3.1 specification as an OpenAPI 3.0 document
+func loadOpenAPI31Spec(path string) (*openapi3.T, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := decodeGenericSpec(data)
+	if err != nil {
+		return nil, err
+	}
+
+	downgradeOpenAPI31(raw)
+
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	loader := &openapi3.Loader{
+		IsExternalRefsAllowed: true,
+	}
+	doc, err := loader.LoadFromDataWithPath(jsonData, &url.URL{Path: absPath})
+	if err != nil {
+		return nil, err
+	}
+	err = doc.Validate(loader.Context)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI 3.1 document (after conversion to 3.0): %w", err)
+	}
+
+	for _, item := range doc.Paths {
+		for _, op := range item.Operations() {
+			if def := op.Responses["default"]; def != nil && def.Value != nil && def.Value.Extensions[omittedResponsesExtension] != nil {
+				op.Responses = openapi3.Responses{}
+			}
+		}
+	}
+	return doc, nil
+}
+
+// omittedResponsesExtension marks the placeholder response of an operation
+// declaring no responses
+const omittedResponsesExtension = "x-openapi-tester-omitted-responses"
+
+// JSON Schema 2020-12 keywords that have no OpenAPI 3.0 equivalent and are dropped
+var openAPI31DroppedKeywords = []string{
+	"$schema", "$id", "$anchor", "$dynamicRef", "$dynamicAnchor", "$comment", "$vocabulary",
+	"unevaluatedProperties", "unevaluatedItems", "dependentRequired", "dependentSchemas",
+	"if", "then", "else", "propertyNames", "patternProperties",
+	"contains", "minContains", "maxContains", "contentSchema",
+}
+
+// Keywords that only apply to one JSON type, used when splitting a type array
+var openAPI31TypeKeywords = map[string][]string{
+	"string":  {"minLength", "maxLength", "pattern", "format"},
+	"integer": {"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf", "format"},
+	"number":  {"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf", "format"},
+	"array":   {"items", "minItems", "maxItems", "uniqueItems"},
+	"object":  {"properties", "required", "additionalProperties", "minProperties", "maxProperties", "discriminator"},
+}
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// openAPI31Downgrader rewrites a generic OpenAPI 3.1 document in place
+type openAPI31Downgrader struct {
+	pathItems map[string]interface{} // components.pathItems, inlined into paths
+	hoisted   map[string]interface{} // $defs moved into components.schemas
+	defNames  map[string]string      // hoisted name of each $defs entry, by owner/name
+	taken     map[string]bool        // component schema names in use
+	owner     string                 // JSON pointer of the top-level schema being rewritten
+}
+
+// downgradeOpenAPI31 rewrites an OpenAPI 3.1 document into OpenAPI 3.0 form
+func downgradeOpenAPI31(doc map[string]interface{}) {
+	d := &openAPI31Downgrader{
+		hoisted:  map[string]interface{}{},
+		defNames: map[string]string{},
+		taken:    map[string]bool{},
+	}
+
+	doc["openapi"] = "3.0.3"
+
+	// Webhooks describe requests the API sends, there is nothing for a client to test
+	delete(doc, "webhooks")
+	delete(doc, "jsonSchemaDialect")
+
+	if info, ok := doc["info"].(map[string]interface{}); ok {
+		delete(info, "summary")
+		if license, ok := info["license"].(map[string]interface{}); ok {
+			delete(license, "identifier")
+		}
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	if components != nil {
+		d.pathItems, _ = components["pathItems"].(map[string]interface{})
+		delete(components, "pathItems")
+
+		schemas := asMap(components["schemas"])
+		for name := range schemas {
+			d.taken[name] = true
+		}
+		// Everything is walked in sorted order so hoisted names that need a
+		// suffix get the same one every run
+		for _, name := range sortedKeys(schemas) {
+			d.rootSchema(schemas[name], "/components/schemas/"+pointerToken(name))
+		}
+
+		parameters := asMap(components["parameters"])
+		for _, name := range sortedKeys(parameters) {
+			d.parameter(parameters[name], "/components/parameters/"+pointerToken(name))
+		}
+		requestBodies := asMap(components["requestBodies"])
+		for _, name := range sortedKeys(requestBodies) {
+			d.content(requestBodies[name], "/components/requestBodies/"+pointerToken(name))
+		}
+		responses := asMap(components["responses"])
+		for _, name := range sortedKeys(responses) {
+			d.content(responses[name], "/components/responses/"+pointerToken(name))
+		}
+		headers := asMap(components["headers"])
+		for _, name := range sortedKeys(headers) {
+			d.parameter(headers[name], "/components/headers/"+pointerToken(name))
+		}
+		callbacks := asMap(components["callbacks"])
+		for _, name := range sortedKeys(callbacks) {
+			d.callback(callbacks[name], "/components/callbacks/"+pointerToken(name))
+		}
+	}
+
+	paths, ok := doc["paths"].(map[string]interface{})
+	if !ok {
+		// Paths are optional in 3.1 but required in 3.0
+		paths = map[string]interface{}{}
+		doc["paths"] = paths
+	}
+	for _, key := range sortedKeys(paths) {
+		paths[key] = d.pathItem(paths[key], "/paths/"+pointerToken(key))
+	}
+
+	if len(d.hoisted) > 0 {
+		if components == nil {
+			components = map[string]interface{}{}
+			doc["components"] = components
+		}
+		schemas, ok := components["schemas"].(map[string]interface{})
+		if !ok {
+			schemas = map[string]interface{}{}
+			components["schemas"] = schemas
+		}
+		for name, s := range d.hoisted {
+			schemas[name] = s
+		}
+	}
+}
+
+// pathItem rewrites a path item found at the JSON pointer at, inlining
+// references to components.pathItems
+func (d *openAPI31Downgrader) pathItem(v interface{}, at string) interface{} {
+	item, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	if ref, ok := item["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/pathItems/") {
+		name := strings.TrimPrefix(ref, "#/components/pathItems/")
+		if target, ok := d.pathItems[name].(map[string]interface{}); ok {
+			inlined := make(map[string]interface{}, len(target))
+			for k, val := range target {
+				inlined[k] = val
+			}
+			item = inlined
+		}
+	}
+
+	for i, p := range asSlice(item["parameters"]) {
+		d.parameter(p, fmt.Sprintf("%s/parameters/%d", at, i))
+	}
+
+	for _, method := range httpMethods {
+		op, ok := item[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		opAt := at + "/" + method
+		// Responses are optional in 3.1 but 3.0 requires at least one, a
+		// placeholder is added and dropped again once the document is loaded
+		if _, ok := op["responses"]; !ok {
+			op["responses"] = map[string]interface{}{
+				"default": map[string]interface{}{"description": "", omittedResponsesExtension: true},
+			}
+		}
+		for i, p := range asSlice(op["parameters"]) {
+			d.parameter(p, fmt.Sprintf("%s/parameters/%d", opAt, i))
+		}
+		d.content(op["requestBody"], opAt+"/requestBody")
+		responses := asMap(op["responses"])
+		for _, code := range sortedKeys(responses) {
+			d.content(responses[code], opAt+"/responses/"+pointerToken(code))
+		}
+		callbacks := asMap(op["callbacks"])
+		for _, name := range sortedKeys(callbacks) {
+			d.callback(callbacks[name], opAt+"/callbacks/"+pointerToken(name))
+		}
+	}
+
+	return item
+}
+
+// callback rewrites the path items of a callback object
+func (d *openAPI31Downgrader) callback(v interface{}, at string) {
+	cb, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, expr := range sortedKeys(cb) {
+		cb[expr] = d.pathItem(cb[expr], at+"/"+pointerToken(expr))
+	}
+}
+
+// parameter rewrites a parameter or header object
+func (d *openAPI31Downgrader) parameter(v interface{}, at string) {
+	p, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if d.reference(p) {
+		return
+	}
+	d.rootSchema(p["schema"], at+"/schema")
+	d.content(p, at)
+}
+
+// content rewrites the media types and headers of a request body, response or parameter
+func (d *openAPI31Downgrader) content(v interface{}, at string) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if d.reference(obj) {
+		return
+	}
+	headers := asMap(obj["headers"])
+	for _, name := range sortedKeys(headers) {
+		d.parameter(headers[name], at+"/headers/"+pointerToken(name))
+	}
+	content := asMap(obj["content"])
+	for _, name := range sortedKeys(content) {
+		mediaType, ok := content[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mtAt := at + "/content/" + pointerToken(name)
+		d.rootSchema(mediaType["schema"], mtAt+"/schema")
+		encodings := asMap(mediaType["encoding"])
+		for _, prop := range sortedKeys(encodings) {
+			if encoding, ok := encodings[prop].(map[string]interface{}); ok {
+				encHeaders := asMap(encoding["headers"])
+				for _, h := range sortedKeys(encHeaders) {
+					d.parameter(encHeaders[h], mtAt+"/encoding/"+pointerToken(prop)+"/headers/"+pointerToken(h))
+				}
+			}
+		}
+	}
+}
+
+// reference strips the summary/description siblings 3.1 allows next to a
+// non-schema $ref and reports whether the object is a reference
+func (d *openAPI31Downgrader) reference(obj map[string]interface{}) bool {
+	if _, ok := obj["$ref"]; !ok {
+		return false
+	}
+	for k := range obj {
+		if k != "$ref" {
+			delete(obj, k)
+		}
+	}
+	return true
+}
+
+// rootSchema rewrites a top-level schema found at the JSON pointer at, which
+// owns the $defs declared anywhere inside it
+func (d *openAPI31Downgrader) rootSchema(v interface{}, at string) {
+	owner := d.owner
+	d.owner = at
+	d.schema(v)
+	d.owner = owner
+}
+
+// schema rewrites a JSON Schema 2020-12 schema into an OpenAPI 3.0 schema
+func (d *openAPI31Downgrader) schema(v interface{}) {
+	s, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	// Hoist $defs first so references to them can be rewritten
+	defs := asMap(s["$defs"])
+	for _, name := range sortedKeys(defs) {
+		d.schema(defs[name])
+		d.hoisted[d.defName(d.owner, name)] = defs[name]
+	}
+	delete(s, "$defs")
+
+	if ref, ok := s["$ref"].(string); ok {
+		s["$ref"] = d.rewriteRef(ref)
+		d.refSiblings(s)
+		return
+	}
+
+	for _, k := range openAPI31DroppedKeywords {
+		delete(s, k)
+	}
+
+	// Nested schemas
+	for _, p := range asMap(s["properties"]) {
+		d.schema(p)
+	}
+	for _, k := range []string{"allOf", "oneOf", "anyOf", "prefixItems"} {
+		for _, sub := range asSlice(s[k]) {
+			d.schema(sub)
+		}
+	}
+	for _, k := range []string{"items", "not", "additionalProperties"} {
+		d.schema(s[k])
+	}
+
+	if c, ok := s["const"]; ok {
+		if _, hasEnum := s["enum"]; !hasEnum {
+			s["enum"] = []interface{}{c}
+		}
+		delete(s, "const")
+	}
+
+	if examples, ok := s["examples"].([]interface{}); ok {
+		if _, hasExample := s["example"]; !hasExample && len(examples) > 0 {
+			s["example"] = examples[0]
+		}
+		delete(s, "examples")
+	}
+
+	d.exclusiveBound(s, "exclusiveMinimum", "minimum")
+	d.exclusiveBound(s, "exclusiveMaximum", "maximum")
+	d.prefixItems(s)
+
+	// Binary content is declared with contentEncoding/contentMediaType in 3.1
+	if _, hasFormat := s["format"]; !hasFormat {
+		if enc, ok := s["contentEncoding"].(string); ok && strings.EqualFold(enc, "base64") {
+			s["format"] = "byte"
+		} else if _, ok := s["contentMediaType"]; ok {
+			s["format"] = "binary"
+		}
+	}
+	delete(s, "contentEncoding")
+	delete(s, "contentMediaType")
+
+	d.typeArray(s)
+}
+
+// refSiblings turns a $ref with constraining siblings into an allOf, since
+// OpenAPI 3.0 ignores everything next to a $ref
+func (d *openAPI31Downgrader) refSiblings(s map[string]interface{}) {
+	extra := map[string]interface{}{}
+	for k, val := range s {
+		switch k {
+		case "$ref", "description", "summary", "title", "examples", "$comment":
+		default:
+			extra[k] = val
+		}
+	}
+
+	ref := s["$ref"]
+	for k := range s {
+		if k != "$ref" {
+			delete(s, k)
+		}
+	}
+	if len(extra) == 0 {
+		return
+	}
+
+	delete(s, "$ref")
+	for k, val := range extra {
+		s[k] = val
+	}
+	s["allOf"] = append([]interface{}{map[string]interface{}{"$ref": ref}}, asSlice(extra["allOf"])...)
+	d.schema(s)
+}
+
+// exclusiveBound converts a numeric exclusiveMinimum/exclusiveMaximum into
+// the 3.0 form of a bound plus a boolean flag
+func (d *openAPI31Downgrader) exclusiveBound(s map[string]interface{}, exclusiveKey, boundKey string) {
+	val, ok := s[exclusiveKey]
+	if !ok {
+		return
+	}
+	if _, isBool := val.(bool); isBool {
+		return
+	}
+	s[boundKey] = val
+	s[exclusiveKey] = true
+}
+
+// prefixItems maps tuple validation onto a single items schema accepting any
+// of the positional schemas
+func (d *openAPI31Downgrader) prefixItems(s map[string]interface{}) {
+	prefix := asSlice(s["prefixItems"])
+	delete(s, "prefixItems")
+
+	// items: false/true are valid in 3.1 but must be a schema in 3.0
+	if _, isBool := s["items"].(bool); isBool {
+		delete(s, "items")
+	}
+	if len(prefix) == 0 {
+		return
+	}
+
+	variants := prefix
+	if items, ok := s["items"].(map[string]interface{}); ok {
+		variants = append(variants, items)
+	}
+	if len(variants) == 1 {
+		s["items"] = variants[0]
+	} else {
+		s["items"] = map[string]interface{}{"oneOf": variants}
+	}
+	if _, ok := s["type"]; !ok {
+		s["type"] = "array"
+	}
+}
+
+// typeArray maps `type: [..., "null"]` onto nullable and multi-type unions onto
+// a oneOf with one single-typed variant per type
+func (d *openAPI31Downgrader) typeArray(s map[string]interface{}) {
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+	default:
+		return
+	}
+
+	var nonNull []string
+	for _, t := range types {
+		if t == "null" {
+			s["nullable"] = true
+		} else {
+			nonNull = append(nonNull, t)
+		}
+	}
+
+	switch len(nonNull) {
+	case 0:
+		delete(s, "type")
+		return
+	case 1:
+		s["type"] = nonNull[0]
+		return
+	}
+
+	delete(s, "type")
+	variants := make([]interface{}, 0, len(nonNull))
+	moved := map[string]bool{}
+	for _, t := range nonNull {
+		variant := map[string]interface{}{"type": t}
+		for _, k := range openAPI31TypeKeywords[t] {
+			if val, ok := s[k]; ok {
+				variant[k] = val
+				moved[k] = true
+			}
+		}
+		variants = append(variants, variant)
+	}
+	for k := range moved {
+		delete(s, k)
+	}
+
+	if _, hasOneOf := s["oneOf"]; hasOneOf {
+		s["allOf"] = append(asSlice(s["allOf"]), map[string]interface{}{"oneOf": variants})
+	} else {
+		s["oneOf"] = variants
+	}
+}
+
+// rewriteRef points references into $defs at their hoisted component schema
+func (d *openAPI31Downgrader) rewriteRef(ref string) string {
+	const schemasPrefix = "#/components/schemas/"
+
+	idx := strings.LastIndex(ref, "/$defs/")
+	if idx < 0 {
+		return ref
+	}
+	name := ref[idx+len("/$defs/"):]
+	base := ref[:idx]
+
+	owner := d.owner
+	if strings.HasPrefix(base, schemasPrefix) {
+		owner = "/components/schemas/" + strings.SplitN(strings.TrimPrefix(base, schemasPrefix), "/", 2)[0]
+	} else if base != "#" {
+		return ref
+	}
+
+	return schemasPrefix + d.defName(owner, name)
+}
+
+// defName is the component name a $defs entry of the schema at the JSON
+// pointer owner is hoisted to: component_name for component schemas and name
+// elsewhere, with a numeric suffix when a component schema or another hoisted
+// entry already uses that name
+func (d *openAPI31Downgrader) defName(owner, name string) string {
+	key := owner + "/$defs/" + pointerToken(name)
+	if hoisted, ok := d.defNames[key]; ok {
+		return hoisted
+	}
+
+	base := name
+	if component := strings.TrimPrefix(owner, "/components/schemas/"); component != owner {
+		base = unescapePointerToken(component) + "_" + name
+	}
+	hoisted := base
+	for i := 2; d.taken[hoisted]; i++ {
+		hoisted = fmt.Sprintf("%s_%d", base, i)
+	}
+	d.taken[hoisted] = true
+	d.defNames[key] = hoisted
+	return hoisted
+}
+
+// pointerToken escapes a name for use as a JSON pointer reference token
+func pointerToken(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+// unescapePointerToken reverses pointerToken
+func unescapePointerToken(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
@@ -0,0 +1,165 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDowngradeOpenAPI31HoistsDefs(t *testing.T) {
+	const doc = `{
+		"openapi": "3.1.0",
+		"paths": {
+			"/b": {"post": {"requestBody": {"content": {"application/json": {"schema": {
+				"$defs": {"Item": {"type": "string"}},
+				"properties": {"item": {"$ref": "#/$defs/Item"}}
+			}}}}}},
+			"/a": {"post": {"requestBody": {"content": {"application/json": {"schema": {
+				"$defs": {"Item": {"type": "integer"}},
+				"properties": {"item": {"$ref": "#/$defs/Item"}}
+			}}}}}}
+		},
+		"components": {"schemas": {
+			"Item": {"type": "boolean"},
+			"Pet": {
+				"$defs": {"Tag": {"type": "number"}},
+				"properties": {"tag": {"$ref": "#/components/schemas/Pet/$defs/Tag"}}
+			},
+			"Pet_Tag": {"type": "object"}
+		}}
+	}`
+
+	tests := []struct {
+		name    string
+		ref     []string // path to the rewritten $ref
+		want    string   // hoisted component the $ref points at
+		hoisted string   // type of the hoisted component
+	}{
+		{
+			name:    "inline defs of the first path in sorted order",
+			ref:     []string{"paths", "/a", "post", "requestBody", "content", "application/json", "schema", "properties", "item", "$ref"},
+			want:    "Item_2",
+			hoisted: "integer",
+		},
+		{
+			name:    "same-named inline defs of another path",
+			ref:     []string{"paths", "/b", "post", "requestBody", "content", "application/json", "schema", "properties", "item", "$ref"},
+			want:    "Item_3",
+			hoisted: "string",
+		},
+		{
+			name:    "component defs clashing with a component schema",
+			ref:     []string{"components", "schemas", "Pet", "properties", "tag", "$ref"},
+			want:    "Pet_Tag_2",
+			hoisted: "number",
+		},
+	}
+
+	// Hoisted names must not depend on map iteration order
+	for run := 0; run < 10; run++ {
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(doc), &parsed); err != nil {
+			t.Fatal(err)
+		}
+		downgradeOpenAPI31(parsed)
+		schemas := parsed["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+		for _, tt := range tests {
+			var v interface{} = parsed
+			for _, key := range tt.ref {
+				v = v.(map[string]interface{})[key]
+			}
+			if v != "#/components/schemas/"+tt.want {
+				t.Fatalf("%s: $ref = %v, want #/components/schemas/%s", tt.name, v, tt.want)
+			}
+			hoisted, _ := schemas[tt.want].(map[string]interface{})
+			if hoisted["type"] != tt.hoisted {
+				t.Fatalf("%s: %s = %v, want type %s", tt.name, tt.want, hoisted, tt.hoisted)
+			}
+		}
+	}
+}
+
+func TestOpenAPI31OptionalResponses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	writeFile(t, path, `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/x": {"get": {}, "post": {"responses": {"201": {"description": "created"}}}}}}`)
+	endpoints, err := (&OpenAPI31Processor{}).ProcessFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("got %d endpoints, want 2", len(endpoints))
+	}
+	for _, ec := range endpoints {
+		want := 0
+		if ec.Method == "POST" {
+			want = 1
+		}
+		if len(ec.Responses) != want {
+			t.Errorf("%s: got responses %+v, want %d", ec.Method, ec.Responses, want)
+		}
+	}
+}
+
+func TestOpenAPI31Schemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		dataType string
+		nullable bool
+		enum     []interface{}
+		check    func(c Constraints) bool
+	}{
+		{name: "nullable type array", schema: `{"type": ["string", "null"]}`, dataType: "string", nullable: true},
+		{name: "type union", schema: `{"type": ["integer", "string"], "minimum": 1, "maxLength": 3}`, dataType: "integer|string"},
+		{name: "nullable type union", schema: `{"type": ["integer", "string", "null"]}`, dataType: "integer|string", nullable: true},
+		{name: "oneOf of primitives", schema: `{"oneOf": [{"type": "boolean"}, {"type": "number"}]}`, dataType: "boolean|number"},
+		{name: "const", schema: `{"type": "string", "const": "fixed"}`, dataType: "string", enum: []interface{}{"fixed"}},
+		{
+			name: "numeric exclusive bounds", schema: `{"type": "number", "exclusiveMinimum": 0, "exclusiveMaximum": 10}`, dataType: "number",
+			check: func(c Constraints) bool {
+				return c.ExclusiveMinimum && c.ExclusiveMaximum && *c.Minimum == 0 && *c.Maximum == 10
+			},
+		},
+		{name: "prefixItems", schema: `{"type": "array", "prefixItems": [{"type": "integer"}, {"type": "string"}]}`, dataType: "array[integer|string]"},
+		{
+			name: "examples", schema: `{"type": "string", "examples": ["first", "second"]}`, dataType: "string",
+			check: func(c Constraints) bool { return c.Example == "first" },
+		},
+		{
+			name: "ref with siblings", schema: `{"$ref": "#/components/schemas/Code", "maxLength": 4}`, dataType: "string",
+			check: func(c Constraints) bool { return *c.MinLength == 2 && *c.MaxLength == 4 },
+		},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("openapi%d.json", i))
+			writeFile(t, path, `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"},
+				"paths": {"/x": {"get": {"parameters": [{"name": "p", "in": "query", "schema": `+tt.schema+`}]}}},
+				"components": {"schemas": {"Code": {"type": "string", "minLength": 2}}},
+				"webhooks": {"ping": {"post": {"responses": {"200": {"description": "ok"}}}}}}`)
+			endpoints, err := (&OpenAPI31Processor{}).ProcessFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(endpoints) != 1 || len(endpoints[0].Cases) != 1 {
+				t.Fatalf("got %+v, want one endpoint with one parameter", endpoints)
+			}
+			c := endpoints[0].Cases[0]
+			if c.DataType != tt.dataType || c.Nullable != tt.nullable {
+				t.Errorf("type %s nullable %v, want %s nullable %v", c.DataType, c.Nullable, tt.dataType, tt.nullable)
+			}
+			if !reflect.DeepEqual(c.EnumValues, tt.enum) {
+				t.Errorf("enum %v, want %v", c.EnumValues, tt.enum)
+			}
+			if tt.check != nil && !tt.check(c.Constraints) {
+				t.Errorf("unexpected constraints %+v", c.Constraints)
+			}
+		})
+	}
+}
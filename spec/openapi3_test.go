@@ -0,0 +1,398 @@
+package processor
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestConstraintsClampUnsignedLengths(t *testing.T) {
+	tests := []struct {
+		name string
+		in   uint64
+		want int64
+	}{
+		{name: "small", in: 5, want: 5},
+		{name: "largest int64", in: math.MaxInt64, want: math.MaxInt64},
+		{name: "above the largest int64", in: math.MaxInt64 + 1, want: math.MaxInt64},
+		{name: "largest uint64", in: math.MaxUint64, want: math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.in
+			c := constraintsFromOpenAPI3Schema(&openapi3.Schema{
+				MinLength: in,
+				MaxLength: &in,
+				MinItems:  in,
+				MaxItems:  &in,
+			})
+			for name, got := range map[string]*int64{"minLength": c.MinLength, "maxLength": c.MaxLength, "minItems": c.MinItems, "maxItems": c.MaxItems} {
+				if got == nil || *got != tt.want {
+					t.Errorf("%s = %v, want %d", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNestedBodyFields(t *testing.T) {
+	const definitions = `{
+		"Order": {"type": "object", "required": ["customer"], "properties": {
+			"customer": {"$ref": "#/definitions/Customer"},
+			"lines": {"type": "array", "items": {"type": "object", "required": ["sku"], "properties": {
+				"sku": {"type": "string"}, "qty": {"type": "integer"}}}}}},
+		"Customer": {"type": "object", "required": ["name"], "properties": {
+			"name": {"type": "string"},
+			"address": {"type": "object", "properties": {"city": {"type": "string"}}}}},
+		"Node": {"type": "object", "properties": {
+			"value": {"type": "integer"},
+			"next": {"$ref": "#/definitions/Node"}}}}`
+	tests := []struct {
+		name     string
+		schema   string
+		maxDepth int
+		want     []string
+	}{
+		{
+			name:   "nested objects and array items",
+			schema: "Order",
+			want: []string{
+				"customer object required",
+				"customer.address object",
+				"customer.address.city string",
+				"customer.name string required",
+				"lines array[object]",
+				"lines[].qty integer",
+				"lines[].sku string required",
+			},
+		},
+		{
+			name:     "top level only",
+			schema:   "Order",
+			maxDepth: 1,
+			want:     []string{"customer object required", "lines array[object]"},
+		},
+		{
+			name:     "array items take a level",
+			schema:   "Order",
+			maxDepth: 2,
+			want:     []string{"customer object required", "customer.address object", "customer.name string required", "lines array[object]"},
+		},
+		{
+			name:   "self-referencing schema",
+			schema: "Node",
+			want:   []string{"next object", "value integer"},
+		},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swagger := filepath.Join(dir, fmt.Sprintf("swagger%d.json", i))
+			writeFile(t, swagger, `{"swagger": "2.0", "info": {"title": "t", "version": "1"},
+				"paths": {"/orders": {"post": {"parameters": [{"name": "order", "in": "body", "schema": {"$ref": "#/definitions/`+tt.schema+`"}}],
+					"responses": {"200": {"description": "ok"}}}}},
+				"definitions": `+definitions+`}`)
+			openapi := filepath.Join(dir, fmt.Sprintf("openapi%d.json", i))
+			writeFile(t, openapi, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"},
+				"paths": {"/orders": {"post": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/`+tt.schema+`"}}}},
+					"responses": {"200": {"description": "ok"}}}}},
+				"components": {"schemas": `+strings.ReplaceAll(definitions, "#/definitions/", "#/components/schemas/")+`}}`)
+
+			for name, p := range map[string]SpecProcessor{
+				"swagger 2.0": &Swagger2Processor{MaxDepth: tt.maxDepth},
+				"openapi 3.0": &OpenAPI3Processor{MaxDepth: tt.maxDepth},
+			} {
+				path := swagger
+				if name == "openapi 3.0" {
+					path = openapi
+				}
+				if got := bodyFields(t, p, path); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s: got %q, want %q", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBodyMediaTypesOpenAPI3(t *testing.T) {
+	const form = `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	writeFile(t, path, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/pets": {"post": {"requestBody": {"content": {
+			"application/xml": {"schema": `+form+`},
+			"application/x-www-form-urlencoded": {"schema": `+form+`},
+			"application/vnd.api+json": {"schema": `+form+`},
+			"multipart/form-data": {
+				"schema": {"type": "object", "properties": {
+					"photo": {"type": "string", "format": "binary"},
+					"extra": {"type": "array", "items": {"type": "string", "format": "binary"}}}},
+				"encoding": {"photo": {"contentType": "image/png, image/jpeg"}}},
+			"image/png": {"schema": {"type": "string", "format": "binary"}}}},
+			"responses": {"200": {"description": "ok"}}}}}}`)
+
+	endpoints, err := (&OpenAPI3Processor{}).ProcessFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("got %d endpoints, want 1", len(endpoints))
+	}
+	wantMediaTypes := []string{"application/vnd.api+json", "application/x-www-form-urlencoded", "application/xml", "image/png", "multipart/form-data"}
+	if !reflect.DeepEqual(endpoints[0].MediaTypes, wantMediaTypes) {
+		t.Errorf("media types = %q, want %q", endpoints[0].MediaTypes, wantMediaTypes)
+	}
+
+	var got []string
+	for _, c := range endpoints[0].Cases {
+		got = append(got, fmt.Sprintf("%s %s %s %q", c.MediaType, c.ParamName, c.DataType, c.FileTypes))
+	}
+	want := []string{
+		`application/vnd.api+json name string []`,
+		`application/x-www-form-urlencoded name string []`,
+		`application/xml name string []`,
+		`image/png body file ["image/png"]`,
+		`multipart/form-data extra array[file] []`,
+		`multipart/form-data photo file ["image/png" "image/jpeg"]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// variantSummary lists the variant groups and the cases of an endpoint, each
+// case with the variant it belongs to
+func variantSummary(ep EndpointCases) []string {
+	var out []string
+	for _, g := range ep.Variants {
+		line := fmt.Sprintf("group %q %s %q:", g.Field, g.Kind, g.Discriminator)
+		for _, v := range g.Variants {
+			line += " " + v.Name + "=" + v.DiscriminatorValue
+		}
+		out = append(out, line)
+	}
+	for _, c := range ep.Cases {
+		out = append(out, fmt.Sprintf("%s [%s] %s %v", c.ParamName, c.Variant, c.DataType, c.EnumValues))
+	}
+	return out
+}
+
+func TestBodyVariantsOpenAPI3(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	writeFile(t, path, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"},
+		"paths": {
+			"/mapped": {"post": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}},
+				"responses": {"200": {"description": "ok"}}}},
+			"/inline": {"post": {"requestBody": {"content": {"application/json": {"schema": {"type": "object", "properties": {
+				"shape": {"anyOf": [
+					{"title": "Circle", "type": "object", "properties": {"r": {"type": "number"}}},
+					{"type": "object", "required": ["w"], "properties": {"w": {"type": "number"}}}]}}}}}},
+				"responses": {"200": {"description": "ok"}}}},
+			"/extended": {"post": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Shape"}}}},
+				"responses": {"200": {"description": "ok"}}}}},
+		"components": {"schemas": {
+			"Pet": {"oneOf": [{"$ref": "#/components/schemas/Cat"}, {"$ref": "#/components/schemas/Dog"}],
+				"discriminator": {"propertyName": "petType", "mapping": {"cat": "#/components/schemas/Cat", "kitty": "#/components/schemas/Cat", "dog": "Dog"}}},
+			"Cat": {"type": "object", "required": ["petType"], "properties": {"petType": {"type": "string"}, "meow": {"type": "boolean"}}},
+			"Dog": {"type": "object", "required": ["petType"], "properties": {"petType": {"type": "string"}, "bark": {"type": "boolean"}}},
+			"Shape": {"type": "object", "required": ["kind"], "discriminator": {"propertyName": "kind"}, "properties": {"kind": {"type": "string"}}},
+			"Square": {"allOf": [{"$ref": "#/components/schemas/Shape"}, {"type": "object", "properties": {"side": {"type": "number"}}}]}}}}`)
+	want := map[string][]string{
+		"/mapped": {
+			`group "" oneOf "petType": cat=cat dog=dog`,
+			"meow [cat] boolean []",
+			"petType [cat] string [cat kitty]",
+			"bark [dog] boolean []",
+			"petType [dog] string [dog]",
+		},
+		"/inline": {
+			`group "shape" anyOf "": Circle= variant2=`,
+			"shape [] object []",
+			"shape.r [Circle] number []",
+			"shape.w [variant2] number []",
+		},
+		"/extended": {
+			`group "" oneOf "kind": Square=Square`,
+			"kind [Square] string [Square]",
+			"side [Square] number []",
+		},
+	}
+
+	endpoints, err := (&OpenAPI3Processor{}).ProcessFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]string{}
+	for _, ep := range endpoints {
+		got[ep.Endpoint] = variantSummary(ep)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestConstraintsMatchAcrossFormats(t *testing.T) {
+	// %[1]s opens the parameter's schema in OpenAPI 3 and %[2]s closes it
+	const limit = `{"name": "limit", "in": "query", %[1]s"type": "integer", "minimum": 1, "maximum": 100,
+		"exclusiveMaximum": true, "multipleOf": 5, "default": 10%[2]s, "x-deprecated": true, "deprecated": true}`
+	const tags = `{"name": "tags", "in": "query", %[1]s"type": "array", "minItems": 1, "maxItems": 3, "uniqueItems": true,
+		"items": {"type": "string", "minLength": 2, "maxLength": 8, "pattern": "^[a-z]+$"}%[2]s}`
+	const pet = `{"type": "object", "required": ["id"], "additionalProperties": false, "properties": {
+		"id": {"type": "string", "format": "uuid", "readOnly": true, "example": "3f2a"},
+		"owner": {"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}}}}`
+	want := map[string]Constraints{
+		"limit": {Minimum: float64Ptr(1), Maximum: float64Ptr(100), ExclusiveMaximum: true, MultipleOf: float64Ptr(5),
+			Default: float64(10), Deprecated: true},
+		"tags": {MinItems: int64Ptr(1), MaxItems: int64Ptr(3), UniqueItems: true,
+			Items: &Constraints{MinLength: int64Ptr(2), MaxLength: int64Ptr(8), Pattern: "^[a-z]+$"}},
+		"id":         {Format: "uuid", ReadOnly: true, Example: "3f2a"},
+		"owner":      {Properties: map[string]string{"name": "string", "age": "integer"}, Required: []string{"name"}},
+		"owner.name": {},
+		"owner.age":  {},
+	}
+
+	dir := t.TempDir()
+	swagger := filepath.Join(dir, "swagger.json")
+	writeFile(t, swagger, `{"swagger": "2.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/pets": {"post": {"parameters": [`+fmt.Sprintf(limit, "", "")+`, `+fmt.Sprintf(tags, "", "")+`,
+			{"name": "pet", "in": "body", "schema": `+pet+`}],
+			"responses": {"200": {"description": "ok"}}}}}}`)
+	openapi := filepath.Join(dir, "openapi.json")
+	writeFile(t, openapi, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/pets": {"post": {"parameters": [`+fmt.Sprintf(limit, `"schema": {`, "}")+`, `+fmt.Sprintf(tags, `"schema": {`, "}")+`],
+			"requestBody": {"content": {"application/json": {"schema": `+pet+`}}},
+			"responses": {"200": {"description": "ok"}}}}}}`)
+
+	for name, s := range map[string]struct {
+		p    SpecProcessor
+		path string
+	}{
+		"swagger 2.0": {&Swagger2Processor{}, swagger},
+		"openapi 3.0": {&OpenAPI3Processor{}, openapi},
+	} {
+		t.Run(name, func(t *testing.T) {
+			endpoints, err := s.p.ProcessFile(s.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(endpoints) != 1 {
+				t.Fatalf("got %d endpoints, want 1", len(endpoints))
+			}
+			got := map[string]Constraints{}
+			for _, c := range endpoints[0].Cases {
+				got[c.ParamName] = c.Constraints
+			}
+			for field, w := range want {
+				if !reflect.DeepEqual(got[field], w) {
+					t.Errorf("%s: got %+v, want %+v", field, got[field], w)
+				}
+			}
+		})
+	}
+}
+
+func TestParameterSerializationOpenAPI3(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	writeFile(t, path, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/items/{ids}": {"get": {"parameters": [
+			{"name": "ids", "in": "path", "required": true, "schema": {"type": "array", "items": {"type": "integer"}}},
+			{"name": "tags", "in": "query", "schema": {"type": "array", "items": {"type": "string"}}},
+			{"name": "sort", "in": "query", "style": "pipeDelimited", "explode": false, "schema": {"type": "array", "items": {"type": "string"}}},
+			{"name": "filter", "in": "query", "style": "deepObject", "explode": true, "schema": {"type": "object"}},
+			{"name": "q", "in": "query", "allowReserved": true, "schema": {"type": "string"}},
+			{"name": "X-Ids", "in": "header", "schema": {"type": "array", "items": {"type": "integer"}}},
+			{"name": "session", "in": "cookie", "schema": {"type": "string"}},
+			{"name": "raw", "in": "query", "content": {"application/json": {"schema": {"type": "object"}}}}],
+			"responses": {"200": {"description": "ok"}}}}}}`)
+	want := map[string]string{
+		"ids":     "simple false false",
+		"tags":    "form true false",
+		"sort":    "pipeDelimited false false",
+		"filter":  "deepObject true false",
+		"q":       "form true true",
+		"X-Ids":   "simple false false",
+		"session": "form true false",
+		"raw":     " false false",
+	}
+
+	endpoints, err := (&OpenAPI3Processor{}).ProcessFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("got %d endpoints, want 1", len(endpoints))
+	}
+	if len(endpoints[0].Cases) != len(want) {
+		t.Fatalf("got %d parameters, want %d", len(endpoints[0].Cases), len(want))
+	}
+	for _, c := range endpoints[0].Cases {
+		got := fmt.Sprintf("%s %v %v", c.Style, c.Explode, c.AllowReserved)
+		if got != want[c.ParamName] {
+			t.Errorf("%s: got style, explode and allowReserved %q, want %q", c.ParamName, got, want[c.ParamName])
+		}
+		if c.CollectionFormat != "" {
+			t.Errorf("%s: got collectionFormat %q, want none", c.ParamName, c.CollectionFormat)
+		}
+	}
+}
+
+func TestServersOpenAPI3(t *testing.T) {
+	const variables = `{"url": "https://{region}.example.com/{version}", "variables": {
+		"region": {"default": "eu", "enum": ["us", "eu"]},
+		"version": {"default": "v2"}}}`
+	tests := []struct {
+		name      string
+		document  string // document servers
+		pathItem  string // path item servers
+		operation string // operation servers
+		want      []string
+	}{
+		{name: "none declared"},
+		{name: "document servers", document: `{"url": "https://api.example.com/v1/"}, {"url": "http://localhost:8080"}`,
+			want: []string{"https://api.example.com/v1 /v1", "http://localhost:8080 "}},
+		{name: "path item servers win", document: `{"url": "https://api.example.com"}`, pathItem: `{"url": "https://path.example.com/p"}`,
+			want: []string{"https://path.example.com/p /p"}},
+		{name: "operation servers win", document: `{"url": "https://api.example.com"}`, pathItem: `{"url": "https://path.example.com"}`,
+			operation: `{"url": "https://op.example.com/o"}`, want: []string{"https://op.example.com/o /o"}},
+		{name: "relative URL", document: `{"url": "/api"}`, want: []string{"/api /api"}},
+		{name: "variables with the default first", document: variables,
+			want: []string{"https://eu.example.com/v2 /v2 map[region:eu version:v2]", "https://us.example.com/v2 /v2 map[region:us version:v2]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "openapi.json")
+			writeFile(t, path, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"}, "servers": [`+tt.document+`],
+				"paths": {"/pets": {"servers": [`+tt.pathItem+`], "get": {"servers": [`+tt.operation+`],
+					"responses": {"200": {"description": "ok"}}}}}}`)
+			endpoints, err := (&OpenAPI3Processor{}).ProcessFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(endpoints) != 1 {
+				t.Fatalf("got %d endpoints, want 1", len(endpoints))
+			}
+			var got []string
+			for _, s := range endpoints[0].Servers {
+				server := s.URL + " " + s.BasePath
+				if s.Variables != nil {
+					server += fmt.Sprintf(" %v", s.Variables)
+				}
+				got = append(got, server)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
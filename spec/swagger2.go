@@ -3,22 +3,39 @@ package processor
 import (
 	"encoding/json"
 	"io/ioutil"
+	"strconv"
 	"strings"
 
 	"github.com/go-openapi/spec"
 )
 
 // Swagger2Processor handles Swagger 2.0 specifications
-type Swagger2Processor struct{}
+type Swagger2Processor struct {
+	MaxDepth int // nesting limit for request body fields, DefaultMaxBodyDepth when 0
+	warnings []string
+}
 
-// ProcessFile loads and processes a Swagger 2.0 specification file
+// ProcessFile loads and processes a Swagger 2.0 specification file. References
+// that cannot be resolved do not fail it, they are reported by Warnings.
 func (p *Swagger2Processor) ProcessFile(filename string) ([]EndpointCases, error) {
 	swagger, err := loadSwagger2Spec(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	return extractEndpointsSwagger2(swagger), nil
+	resolver, err := newSwaggerResolver(filename, swagger)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := extractEndpointsSwagger2(swagger, resolver, p.MaxDepth)
+	p.warnings = resolver.warnings
+	return endpoints, nil
+}
+
+// Warnings lists the references the last processed file could not resolve
+func (p *Swagger2Processor) Warnings() []string {
+	return p.warnings
 }
 
 // loadSwagger2Spec loads a Swagger 2.0 specification
@@ -29,7 +46,27 @@ func loadSwagger2Spec(path string) (*spec.Swagger, error) {
 	}
 
 	var swagger spec.Swagger
-	if err := json.Unmarshal(data, &swagger); err != nil {
+	if err := json.Unmarshal(data, &swagger); err == nil {
+		return &swagger, nil
+	}
+
+	// Not valid JSON, load it as YAML and reuse the JSON decoding
+	raw, err := decodeSpecDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unquoted versions such as `swagger: 2.0` are YAML numbers
+	raw["swagger"] = scalarToString(raw["swagger"])
+	if info, ok := raw["info"].(map[string]interface{}); ok {
+		info["version"] = scalarToString(info["version"])
+	}
+
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(jsonData, &swagger); err != nil {
 		return nil, err
 	}
 
@@ -37,10 +74,20 @@ func loadSwagger2Spec(path string) (*spec.Swagger, error) {
 }
 
 // extractEndpointsSwagger2 extracts endpoints from Swagger 2.0 specification
-func extractEndpointsSwagger2(swagger *spec.Swagger) []EndpointCases {
+func extractEndpointsSwagger2(swagger *spec.Swagger, r *swaggerResolver, maxDepth int) []EndpointCases {
 	results := []EndpointCases{}
 
-	for path, pathItem := range swagger.Paths.Paths {
+	if swagger.Paths == nil {
+		return results
+	}
+
+	// Paths are sorted and methods follow canonicalMethods so output is reproducible
+	for _, path := range sortedKeys(swagger.Paths.Paths) {
+		pathItem, ok := r.pathItem(swagger.Paths.Paths[path])
+		if !ok {
+			continue
+		}
+
 		// Handle all operations (GET, POST, PUT, DELETE, etc.)
 		operations := map[string]*spec.Operation{
 			"get":     pathItem.Get,
@@ -52,7 +99,9 @@ func extractEndpointsSwagger2(swagger *spec.Swagger) []EndpointCases {
 			"patch":   pathItem.Patch,
 		}
 
-		for method, operation := range operations {
+		for _, canonical := range canonicalMethods {
+			method := strings.ToLower(canonical)
+			operation := operations[method]
 			if operation == nil {
 				continue
 			}
@@ -64,7 +113,8 @@ func extractEndpointsSwagger2(swagger *spec.Swagger) []EndpointCases {
 			}
 
 			// 1. Extract parameters (query, path, header, formData)
-			allParams := append(operation.Parameters, pathItem.Parameters...)
+			allParams := mergeSwaggerParameters(r.parameters(pathItem.Parameters), r.parameters(operation.Parameters))
+			bodyMediaType, formMediaType := swaggerMediaTypes(swagger, operation, allParams)
 			for _, param := range allParams {
 				// Skip body parameters - we'll handle them separately
 				if param.In == "body" {
@@ -78,6 +128,21 @@ func extractEndpointsSwagger2(swagger *spec.Swagger) []EndpointCases {
 					Description: param.Description,
 					EnumValues:  extractEnumFromSwaggerParam(param),
 					DataType:    extractDataTypeFromSwaggerParam(param),
+					Nullable:    isSwaggerNullable(param.Extensions),
+					Constraints: constraintsFromSwaggerParam(param),
+					Extensions:  vendorExtensions(param.Extensions),
+				}
+				if param.Type == "array" {
+					pc.CollectionFormat = param.CollectionFormat
+					if pc.CollectionFormat == "" {
+						pc.CollectionFormat = "csv"
+					}
+				}
+				if param.In == "formData" {
+					pc.MediaType = formMediaType
+					if !contains(ec.MediaTypes, formMediaType) {
+						ec.MediaTypes = append(ec.MediaTypes, formMediaType)
+					}
 				}
 				ec.Cases = append(ec.Cases, pc)
 			}
@@ -85,7 +150,11 @@ func extractEndpointsSwagger2(swagger *spec.Swagger) []EndpointCases {
 			// 2. Extract request body (body parameters in Swagger 2.0)
 			for _, param := range allParams {
 				if param.In == "body" && param.Schema != nil {
-					bodyCases := extractSwaggerRequestBodyCases(path, method, param.Schema, swagger)
+					if !contains(ec.MediaTypes, bodyMediaType) {
+						ec.MediaTypes = append(ec.MediaTypes, bodyMediaType)
+					}
+					bodyCases, variants := extractSwaggerRequestBodyCases(path, method, bodyMediaType, param.Required, param.Schema, r, bodyDepth(maxDepth))
+					ec.Variants = append(ec.Variants, variants...)
 					// If we successfully extracted individual properties, use them
 					// Otherwise, fall back to treating it as a generic body parameter
 					if len(bodyCases) > 0 {
@@ -99,12 +168,23 @@ func extractEndpointsSwagger2(swagger *spec.Swagger) []EndpointCases {
 							Description: param.Description,
 							EnumValues:  nil,
 							DataType:    "object",
+							MediaType:   bodyMediaType,
+							Extensions:  vendorExtensions(param.Extensions),
 						}
 						ec.Cases = append(ec.Cases, pc)
 					}
 				}
 			}
 
+			// 3. Extract documented responses
+			ec.Responses = extractSwaggerResponses(swagger, operation, r, bodyDepth(maxDepth))
+
+			// 4. Extract security requirements
+			ec.Security, ec.AnonymousAllowed = swaggerSecurity(swagger, operation)
+
+			// 5. Resolve the server the endpoint is served under
+			ec.Servers = swaggerServers(swagger, operation)
+
 			results = append(results, ec)
 		}
 	}
@@ -112,44 +192,478 @@ func extractEndpointsSwagger2(swagger *spec.Swagger) []EndpointCases {
 	return results
 }
 
-// extractSwaggerRequestBodyCases extracts request body cases from Swagger 2.0 schema
-func extractSwaggerRequestBodyCases(path, method string, schema *spec.Schema, swagger *spec.Swagger) []ParameterCase {
+// swaggerMediaTypes picks the media type of an operation's body parameter
+// (JSON preferred) and of its formData parameters from the operation's or the
+// document's consumes list
+func swaggerMediaTypes(swagger *spec.Swagger, operation *spec.Operation, params []spec.Parameter) (bodyMediaType, formMediaType string) {
+	consumes := operation.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+
+	formMediaType = "application/x-www-form-urlencoded"
+	for _, mt := range orderMediaTypes(consumes) {
+		switch baseMediaType(mt) {
+		case "multipart/form-data":
+			formMediaType = mt
+		case "application/x-www-form-urlencoded":
+		default:
+			if bodyMediaType == "" {
+				bodyMediaType = mt
+			}
+		}
+	}
+	if bodyMediaType == "" {
+		bodyMediaType = "application/json"
+	}
+
+	// File parameters can only be sent as multipart
+	for _, param := range params {
+		if param.In == "formData" && param.Type == "file" {
+			formMediaType = "multipart/form-data"
+		}
+	}
+
+	return bodyMediaType, formMediaType
+}
+
+// swaggerServers builds the server of an operation from the document's host
+// and basePath, with the operation's or else the document's first scheme
+// (https when none is declared). A document without host and basePath has no
+// server.
+func swaggerServers(swagger *spec.Swagger, operation *spec.Operation) []Server {
+	basePath := strings.TrimSuffix(swagger.BasePath, "/")
+	if swagger.Host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []Server{{URL: basePath, BasePath: basePath}}
+	}
+
+	schemes := operation.Schemes
+	if len(schemes) == 0 {
+		schemes = swagger.Schemes
+	}
+	scheme := "https"
+	if len(schemes) > 0 {
+		scheme = schemes[0]
+	}
+	return []Server{{URL: scheme + "://" + swagger.Host + basePath, BasePath: basePath}}
+}
+
+// swaggerSecurity resolves the security requirements of an operation,
+// inheriting the document's when the operation declares none. The basic
+// scheme is reported as http with the basic authentication scheme, as in
+// OpenAPI 3.
+func swaggerSecurity(swagger *spec.Swagger, operation *spec.Operation) ([]SecurityRequirement, bool) {
+	requirements := swagger.Security
+	anonymous := false
+	if operation.Security != nil {
+		requirements = operation.Security
+		anonymous = len(requirements) == 0
+	}
+
+	var out []SecurityRequirement
+	for _, requirement := range requirements {
+		if len(requirement) == 0 {
+			anonymous = true
+			continue
+		}
+		var req SecurityRequirement
+		for _, name := range sortedKeys(requirement) {
+			def := swagger.SecurityDefinitions[name]
+			if def == nil {
+				continue
+			}
+			scheme := SecurityScheme{
+				Name:      name,
+				Type:      def.Type,
+				In:        def.In,
+				ParamName: def.Name,
+				Scopes:    requirement[name],
+			}
+			if def.Type == "basic" {
+				scheme.Type, scheme.Scheme = "http", "basic"
+			}
+			req.Schemes = append(req.Schemes, scheme)
+		}
+		if len(req.Schemes) > 0 {
+			out = append(out, req)
+		}
+	}
+
+	return out, anonymous
+}
+
+// extractSwaggerResponses describes every documented response of an
+// operation, with the media types of the operation's or the document's
+// produces list and the required fields of the response schema
+func extractSwaggerResponses(swagger *spec.Swagger, operation *spec.Operation, r *swaggerResolver, maxDepth int) []ResponseCase {
+	if operation.Responses == nil {
+		return nil
+	}
+
+	produces := operation.Produces
+	if len(produces) == 0 {
+		produces = swagger.Produces
+	}
+
+	responses := map[string]spec.Response{}
+	for code, resp := range operation.Responses.StatusCodeResponses {
+		responses[strconv.Itoa(code)] = resp
+	}
+	if operation.Responses.Default != nil {
+		responses["default"] = *operation.Responses.Default
+	}
+	codes := sortedKeys(responses)
+	sortStatusCodes(codes)
+
+	var out []ResponseCase
+	for _, code := range codes {
+		resp, ok := r.response(responses[code])
+		if !ok {
+			continue
+		}
+
+		rc := ResponseCase{
+			StatusCode:  code,
+			Description: resp.Description,
+			Headers:     sortedKeys(resp.Headers),
+		}
+		if resp.Schema != nil {
+			rc.MediaTypes = orderMediaTypes(produces)
+			rc.RequiredFields = swaggerRequiredFields(r, *resp.Schema, "", maxDepth, map[string]bool{})
+		}
+		out = append(out, rc)
+	}
+
+	return out
+}
+
+// swaggerRequiredFields lists the required properties of a response schema
+// and, below them, those of nested objects and array items (named items[]),
+// up to maxDepth levels
+func swaggerRequiredFields(r *swaggerResolver, schema spec.Schema, prefix string, maxDepth int, visited map[string]bool) []string {
+	resolved, refKey, ok := r.schema(schema)
+	if !ok || maxDepth <= 0 || visited[refKey] {
+		return nil
+	}
+	if refKey != "" {
+		visited[refKey] = true
+		defer delete(visited, refKey)
+	}
+
+	if resolved.Type.Contains("array") && resolved.Items != nil && resolved.Items.Schema != nil {
+		return swaggerRequiredFields(r, *resolved.Items.Schema, strings.TrimSuffix(prefix, ".")+"[].", maxDepth-1, visited)
+	}
+
+	var fields []string
+	for _, name := range sortedKeys(resolved.Properties) {
+		if !contains(resolved.Required, name) {
+			continue
+		}
+		fields = append(fields, prefix+name)
+		fields = append(fields, swaggerRequiredFields(r, resolved.Properties[name], prefix+name+".", maxDepth-1, visited)...)
+	}
+	return fields
+}
+
+// mergeSwaggerParameters combines path-level and operation-level parameters.
+// Operation parameters come first in declaration order, followed by the path
+// parameters the operation does not override by name and location.
+func mergeSwaggerParameters(pathParams, opParams []spec.Parameter) []spec.Parameter {
+	merged := []spec.Parameter{}
+	seen := map[string]bool{}
+
+	for _, params := range [][]spec.Parameter{opParams, pathParams} {
+		for _, param := range params {
+			key := parameterKey(param.Name, param.In)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, param)
+		}
+	}
+
+	return merged
+}
+
+// extractSwaggerRequestBodyCases extracts request body cases from Swagger 2.0 schema,
+// descending into nested objects and array items up to maxDepth levels. A body
+// without properties (e.g. a top-level array) yields a single case named body,
+// followed by its nested fields, as in OpenAPI 3. A schema with a
+// discriminator is returned as a variant group of the definitions extending
+// it, with the fields of each variant tagged with its name.
+func extractSwaggerRequestBodyCases(path, method, mediaType string, required bool, schema *spec.Schema, r *swaggerResolver, maxDepth int) ([]ParameterCase, []VariantGroup) {
 	out := []ParameterCase{}
 	if schema == nil {
+		return out, nil
+	}
+
+	actualSchema, refKey, ok := r.schema(*schema)
+	if !ok {
+		// Reference not found, return empty
+		return out, nil
+	}
+
+	w := &swaggerBodyWalker{
+		resolver:  r,
+		mediaType: mediaType,
+		maxDepth:  maxDepth,
+		visited:   map[string]bool{},
+	}
+	if refKey != "" {
+		w.visited[refKey] = true
+	}
+
+	if subtypes := w.polymorphic(actualSchema, refKey); len(subtypes) > 0 {
+		return w.composition(out, "", actualSchema, subtypes, 1), w.groups
+	}
+
+	if len(actualSchema.Properties) == 0 {
+		out = append(out, w.fieldCase("body", required, actualSchema))
+		// The root schema is already marked visited, nested must not skip it
+		delete(w.visited, refKey)
+		return w.nested(out, "body", actualSchema, refKey, 1), w.groups
+	}
+	return w.properties(out, "", actualSchema, 1), w.groups
+}
+
+// swaggerBodyWalker walks the schema of a Swagger 2.0 body parameter
+type swaggerBodyWalker struct {
+	resolver  *swaggerResolver
+	mediaType string
+	maxDepth  int
+	visited   map[string]bool // referenced schemas on the current path, for cycle protection
+	variant   string          // variant currently being walked
+	groups    []VariantGroup
+}
+
+// properties adds a case for each property of an object schema,
+// followed by the cases of its nested fields. A nested field is required when
+// its parent object lists it as required.
+func (w *swaggerBodyWalker) properties(out []ParameterCase, prefix string, schema spec.Schema, depth int) []ParameterCase {
+	for _, name := range sortedKeys(schema.Properties) {
+		propSchema, refKey, ok := w.resolver.schema(schema.Properties[name])
+		if !ok {
+			continue
+		}
+
+		fieldPath := prefix + name
+		out = append(out, w.fieldCase(fieldPath, contains(schema.Required, name), propSchema))
+		out = w.nested(out, fieldPath, propSchema, refKey, depth)
+	}
+
+	return out
+}
+
+// fieldCase builds the case for a single body field
+func (w *swaggerBodyWalker) fieldCase(fieldPath string, required bool, schema spec.Schema) ParameterCase {
+	return ParameterCase{
+		ParamName:   fieldPath,
+		ParamIn:     "body",
+		Required:    required,
+		EnumValues:  extractEnumFromSwaggerSchema(schema),
+		Description: schema.Description,
+		DataType:    w.dataType(schema),
+		Nullable:    isSwaggerNullable(schema.Extensions),
+		MediaType:   w.mediaType,
+		Variant:     w.variant,
+		Constraints: w.constraints(schema),
+		Extensions:  vendorExtensions(schema.Extensions),
+	}
+}
+
+// nested descends into the properties of an object field, the subtypes of a
+// polymorphic field or the item schema of an array field (named items[]).
+// Schemas already on the current path are skipped so self-referencing schemas
+// terminate.
+func (w *swaggerBodyWalker) nested(out []ParameterCase, fieldPath string, schema spec.Schema, refKey string, depth int) []ParameterCase {
+	if depth >= w.maxDepth || w.visited[refKey] {
 		return out
 	}
+	if refKey != "" {
+		w.visited[refKey] = true
+		defer delete(w.visited, refKey)
+	}
 
-	actualSchema := schema
+	if subtypes := w.polymorphic(schema, refKey); len(subtypes) > 0 {
+		return w.composition(out, fieldPath, schema, subtypes, depth+1)
+	}
 
-	// If this is a reference, resolve it
-	if schema.Ref.String() != "" {
-		ref := schema.Ref.String()
-		if strings.HasPrefix(ref, "#/definitions/") {
-			definitionName := strings.TrimPrefix(ref, "#/definitions/")
-			if def, ok := swagger.Definitions[definitionName]; ok {
-				actualSchema = &def
-			} else {
-				// Reference not found, return empty
-				return out
-			}
+	switch {
+	case schema.Type.Contains("array") && schema.Items != nil && schema.Items.Schema != nil:
+		items, itemsKey, ok := w.resolver.schema(*schema.Items.Schema)
+		if !ok {
+			return out
 		}
+		return w.nested(out, fieldPath+"[]", items, itemsKey, depth+1)
+	case len(schema.Properties) > 0:
+		return w.properties(out, fieldPath+".", schema, depth+1)
 	}
 
-	for name, propSchema := range actualSchema.Properties {
-		pc := ParameterCase{
-			ParamName:   name,
-			ParamIn:     "body",
-			Required:    contains(actualSchema.Required, name),
-			EnumValues:  extractEnumFromSwaggerSchema(propSchema),
-			Description: propSchema.Description,
-			DataType:    extractDataTypeFromSwaggerSchema(propSchema),
+	return out
+}
+
+// polymorphic returns the subtypes of a referenced schema with a discriminator
+func (w *swaggerBodyWalker) polymorphic(schema spec.Schema, refKey string) []string {
+	if schema.Discriminator == "" || refKey == "" {
+		return nil
+	}
+	return w.resolver.subtypes(refKey)
+}
+
+// composition records a variant group for a discriminator base and walks the
+// fields of each subtype under the subtype's name. The discriminator value is
+// the definition name unless x-discriminator-value overrides it.
+func (w *swaggerBodyWalker) composition(out []ParameterCase, fieldPath string, base spec.Schema, subtypes []string, depth int) []ParameterCase {
+	// Record the group before walking so nested groups follow it
+	idx := len(w.groups)
+	w.groups = append(w.groups, VariantGroup{
+		Field:         fieldPath,
+		Variant:       w.variant,
+		Kind:          "oneOf",
+		Discriminator: base.Discriminator,
+		MediaType:     w.mediaType,
+	})
+
+	prefix := ""
+	if fieldPath != "" {
+		prefix = fieldPath + "."
+	}
+
+	parent := w.variant
+	defer func() { w.variant = parent }()
+
+	for _, name := range subtypes {
+		ref, err := spec.NewRef("#/definitions/" + name)
+		if err != nil {
+			continue
+		}
+		sub, key, ok := w.resolver.schema(spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}})
+		if !ok || w.visited[key] {
+			continue
 		}
-		out = append(out, pc)
+
+		value := name
+		if v, ok := sub.Extensions.GetString("x-discriminator-value"); ok && v != "" {
+			value = v
+		}
+		w.groups[idx].Variants = append(w.groups[idx].Variants, Variant{Name: name, DiscriminatorValue: value})
+
+		w.variant = joinVariant(parent, name)
+		w.visited[key] = true
+		start := len(out)
+		out = w.properties(out, prefix, sub, depth)
+		delete(w.visited, key)
+		pinDiscriminator(out[start:], prefix+base.Discriminator, w.variant, []interface{}{value})
 	}
 
 	return out
 }
 
+// dataType extracts the data type of a body field, resolving the item schema
+// of arrays so array[Tag] is reported as array[object]
+func (w *swaggerBodyWalker) dataType(schema spec.Schema) string {
+	if schema.Type.Contains("array") && schema.Items != nil && schema.Items.Schema != nil {
+		if items, _, ok := w.resolver.schema(*schema.Items.Schema); ok {
+			return "array[" + extractDataTypeFromSwaggerSchema(items) + "]"
+		}
+	}
+	return extractDataTypeFromSwaggerSchema(schema)
+}
+
+// constraints collects the validation keywords of a body field, resolving the
+// item schema of arrays and the schemas of object properties
+func (w *swaggerBodyWalker) constraints(schema spec.Schema) Constraints {
+	c := constraintsFromSwaggerSchema(schema)
+	if schema.Items != nil && schema.Items.Schema != nil {
+		if items, _, ok := w.resolver.schema(*schema.Items.Schema); ok {
+			itemConstraints := constraintsFromSwaggerSchema(items)
+			c.Items = &itemConstraints
+		}
+	}
+	for name, p := range schema.Properties {
+		if prop, _, ok := w.resolver.schema(p); ok {
+			c.Properties[name] = w.dataType(prop)
+		}
+	}
+	return c
+}
+
+// constraintsFromSwaggerSchema collects the validation keywords of a Swagger 2.0 schema
+func constraintsFromSwaggerSchema(schema spec.Schema) Constraints {
+	c := Constraints{
+		Minimum:          schema.Minimum,
+		Maximum:          schema.Maximum,
+		ExclusiveMinimum: schema.ExclusiveMinimum,
+		ExclusiveMaximum: schema.ExclusiveMaximum,
+		MultipleOf:       schema.MultipleOf,
+		MinLength:        schema.MinLength,
+		MaxLength:        schema.MaxLength,
+		Pattern:          schema.Pattern,
+		Format:           schema.Format,
+		MinItems:         schema.MinItems,
+		MaxItems:         schema.MaxItems,
+		UniqueItems:      schema.UniqueItems,
+		Default:          schema.Default,
+		Example:          schema.Example,
+		ReadOnly:         schema.ReadOnly,
+		Deprecated:       isSwaggerDeprecated(schema.Extensions),
+	}
+	if schema.Items != nil && schema.Items.Schema != nil && schema.Items.Schema.Ref.String() == "" {
+		items := constraintsFromSwaggerSchema(*schema.Items.Schema)
+		c.Items = &items
+	}
+	if len(schema.Properties) > 0 {
+		c.Properties = map[string]string{}
+		for name, p := range schema.Properties {
+			c.Properties[name] = extractDataTypeFromSwaggerSchema(p)
+		}
+	}
+	c.Required = schema.Required
+	if ap := schema.AdditionalProperties; ap != nil && ap.Schema == nil {
+		allows := ap.Allows
+		c.AdditionalProperties = &allows
+	}
+	return c
+}
+
+// constraintsFromSwaggerParam collects the validation keywords of a non-body
+// Swagger 2.0 parameter
+func constraintsFromSwaggerParam(param spec.Parameter) Constraints {
+	c := constraintsFromSwaggerValidations(param.CommonValidations, param.SimpleSchema)
+	c.Deprecated = isSwaggerDeprecated(param.Extensions)
+	return c
+}
+
+// constraintsFromSwaggerValidations collects the validations shared by
+// parameters and their array items
+func constraintsFromSwaggerValidations(v spec.CommonValidations, s spec.SimpleSchema) Constraints {
+	c := Constraints{
+		Minimum:          v.Minimum,
+		Maximum:          v.Maximum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		MultipleOf:       v.MultipleOf,
+		MinLength:        v.MinLength,
+		MaxLength:        v.MaxLength,
+		Pattern:          v.Pattern,
+		Format:           s.Format,
+		MinItems:         v.MinItems,
+		MaxItems:         v.MaxItems,
+		UniqueItems:      v.UniqueItems,
+		Default:          s.Default,
+		Example:          s.Example,
+	}
+	if s.Items != nil {
+		items := constraintsFromSwaggerValidations(s.Items.CommonValidations, s.Items.SimpleSchema)
+		c.Items = &items
+	}
+	return c
+}
+
 // Extract enum values from Swagger 2.0 parameter
 func extractEnumFromSwaggerParam(param spec.Parameter) []interface{} {
 	if param.Enum != nil {
@@ -217,8 +731,8 @@ func extractDataTypeFromSwaggerSchema(schema spec.Schema) string {
 		return "array"
 	}
 
-	// Handle object types
-	if len(schema.Type) > 0 && schema.Type[0] == "object" {
+	// Handle object types, including untyped schemas that declare properties
+	if (len(schema.Type) > 0 && schema.Type[0] == "object") || (len(schema.Type) == 0 && len(schema.Properties) > 0) {
 		return "object"
 	}
 
@@ -229,3 +743,16 @@ func extractDataTypeFromSwaggerSchema(schema spec.Schema) string {
 
 	return "string" // default fallback
 }
+
+// isSwaggerDeprecated reports the x-deprecated vendor extension, Swagger 2.0
+// can only deprecate whole operations
+func isSwaggerDeprecated(ext spec.Extensions) bool {
+	deprecated, ok := ext.GetBool("x-deprecated")
+	return ok && deprecated
+}
+
+// isSwaggerNullable reports the x-nullable vendor extension, Swagger 2.0 has no nullable keyword
+func isSwaggerNullable(ext spec.Extensions) bool {
+	nullable, ok := ext.GetBool("x-nullable")
+	return ok && nullable
+}
@@ -0,0 +1,375 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// swaggerResolver resolves $refs in a Swagger 2.0 specification: local
+// pointers into definitions, parameters and responses, relative files, chains
+// of references and allOf compositions.
+//
+// Every document it loads has its references rewritten to absolute file paths,
+// so a schema taken from another file can be resolved without remembering
+// where it came from. References still starting with # belong to the root file.
+type swaggerResolver struct {
+	root     *spec.Swagger
+	rootPath string
+	docs     map[string]map[string]interface{} // parsed documents by absolute path
+	warnings []string                          // resolution failures, in the order found
+}
+
+// newSwaggerResolver creates a resolver for the specification loaded from path
+func newSwaggerResolver(path string, swagger *spec.Swagger) (*swaggerResolver, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &swaggerResolver{
+		root:     swagger,
+		rootPath: absPath,
+		docs:     map[string]map[string]interface{}{},
+	}, nil
+}
+
+// warn records a resolution failure once. Extraction carries on: schemas
+// that cannot be resolved are read as generic objects, and parameters,
+// responses and path items are skipped.
+func (r *swaggerResolver) warn(err error) {
+	if !contains(r.warnings, err.Error()) {
+		r.warnings = append(r.warnings, err.Error())
+	}
+}
+
+// unresolvedSchema stands in for a schema whose reference cannot be resolved
+func unresolvedSchema() spec.Schema {
+	return spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}}}
+}
+
+// schema follows a chain of references and merges allOf compositions. It
+// returns the resolved schema and the canonical location of the last reference
+// followed, or "" for an inline schema. ok is false when a reference loops back
+// onto itself; a reference that cannot be resolved gives a generic object.
+func (r *swaggerResolver) schema(s spec.Schema) (resolved spec.Schema, key string, ok bool) {
+	return r.resolveSchema(s, map[string]bool{})
+}
+
+func (r *swaggerResolver) resolveSchema(s spec.Schema, stack map[string]bool) (spec.Schema, string, bool) {
+	key := ""
+	for s.Ref.String() != "" {
+		ref := s.Ref.String()
+		target, canonical, err := r.resolveRef(ref)
+		if err != nil {
+			r.warn(err)
+			return unresolvedSchema(), "", true
+		}
+		if stack[canonical] {
+			return s, "", false
+		}
+		stack[canonical] = true
+		defer delete(stack, canonical)
+
+		var next spec.Schema
+		if err := remarshal(target, &next); err != nil {
+			r.warn(fmt.Errorf("reference %q is not a schema: %v", ref, err))
+			return unresolvedSchema(), "", true
+		}
+		s, key = next, canonical
+	}
+
+	if len(s.AllOf) > 0 {
+		s = r.mergeAllOf(s, stack)
+	}
+
+	return s, key, true
+}
+
+// mergeAllOf folds the members of an allOf into a single schema: properties
+// and required lists are combined, and type, format, items, enum and the
+// validation keywords are taken from the first member that declares them.
+// Properties of the schema itself win over those of its members.
+func (r *swaggerResolver) mergeAllOf(s spec.Schema, stack map[string]bool) spec.Schema {
+	merged := s
+	merged.AllOf = nil
+	merged.Properties = spec.SchemaProperties{}
+	for name, p := range s.Properties {
+		merged.Properties[name] = p
+	}
+	merged.Required = append([]string{}, s.Required...)
+
+	for _, member := range s.AllOf {
+		m, _, ok := r.resolveSchema(member, stack)
+		if !ok {
+			continue
+		}
+
+		for name, p := range m.Properties {
+			if _, exists := merged.Properties[name]; !exists {
+				merged.Properties[name] = p
+			}
+		}
+		for _, name := range m.Required {
+			if !contains(merged.Required, name) {
+				merged.Required = append(merged.Required, name)
+			}
+		}
+		if len(merged.Type) == 0 {
+			merged.Type = m.Type
+		}
+		if merged.Format == "" {
+			merged.Format = m.Format
+		}
+		if merged.Items == nil {
+			merged.Items = m.Items
+		}
+		if len(merged.Enum) == 0 {
+			merged.Enum = m.Enum
+		}
+		if merged.Description == "" {
+			merged.Description = m.Description
+		}
+		if merged.Minimum == nil {
+			merged.Minimum, merged.ExclusiveMinimum = m.Minimum, m.ExclusiveMinimum
+		}
+		if merged.Maximum == nil {
+			merged.Maximum, merged.ExclusiveMaximum = m.Maximum, m.ExclusiveMaximum
+		}
+		if merged.MultipleOf == nil {
+			merged.MultipleOf = m.MultipleOf
+		}
+		if merged.MinLength == nil {
+			merged.MinLength = m.MinLength
+		}
+		if merged.MaxLength == nil {
+			merged.MaxLength = m.MaxLength
+		}
+		if merged.Pattern == "" {
+			merged.Pattern = m.Pattern
+		}
+		if merged.MinItems == nil {
+			merged.MinItems = m.MinItems
+		}
+		if merged.MaxItems == nil {
+			merged.MaxItems = m.MaxItems
+		}
+		if merged.Default == nil {
+			merged.Default = m.Default
+		}
+		if merged.Example == nil {
+			merged.Example = m.Example
+		}
+		merged.UniqueItems = merged.UniqueItems || m.UniqueItems
+		merged.ReadOnly = merged.ReadOnly || m.ReadOnly
+	}
+
+	if len(merged.Type) == 0 && len(merged.Properties) > 0 {
+		merged.Type = spec.StringOrArray{"object"}
+	}
+
+	return merged
+}
+
+// subtypes returns the names of the definitions extending the schema at key
+// through allOf, the Swagger 2.0 form of polymorphism selected by a discriminator
+func (r *swaggerResolver) subtypes(key string) []string {
+	var names []string
+	for _, name := range sortedKeys(r.root.Definitions) {
+		for _, member := range r.root.Definitions[name].AllOf {
+			ref := member.Ref.String()
+			if ref == "" {
+				continue
+			}
+			if _, canonical, err := r.resolveRef(ref); err == nil && canonical == key {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// parameters resolves references to shared parameters, dropping the ones that
+// cannot be resolved
+func (r *swaggerResolver) parameters(params []spec.Parameter) []spec.Parameter {
+	out := make([]spec.Parameter, 0, len(params))
+	for _, p := range params {
+		resolved, ok := followRefs(r, p, func(p spec.Parameter) string { return p.Ref.String() })
+		if ok {
+			out = append(out, resolved)
+		}
+	}
+	return out
+}
+
+// response resolves a reference to a shared response
+func (r *swaggerResolver) response(resp spec.Response) (spec.Response, bool) {
+	return followRefs(r, resp, func(resp spec.Response) string { return resp.Ref.String() })
+}
+
+// pathItem resolves a path item defined in another file
+func (r *swaggerResolver) pathItem(item spec.PathItem) (spec.PathItem, bool) {
+	return followRefs(r, item, func(item spec.PathItem) string { return item.Ref.String() })
+}
+
+// followRefs follows a chain of references to a parameter, response or path item
+func followRefs[T any](r *swaggerResolver, v T, refOf func(T) string) (T, bool) {
+	seen := map[string]bool{}
+	for ref := refOf(v); ref != ""; ref = refOf(v) {
+		target, canonical, err := r.resolveRef(ref)
+		if err != nil {
+			r.warn(err)
+			return v, false
+		}
+		if seen[canonical] {
+			r.warn(fmt.Errorf("circular reference %q", ref))
+			return v, false
+		}
+		seen[canonical] = true
+
+		var next T
+		if err := remarshal(target, &next); err != nil {
+			r.warn(fmt.Errorf("reference %q: %v", ref, err))
+			return v, false
+		}
+		v = next
+	}
+	return v, true
+}
+
+// resolveRef returns the value a reference points to and its canonical
+// location as absolute-file#pointer
+func (r *swaggerResolver) resolveRef(ref string) (interface{}, string, error) {
+	file, pointer := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		file, pointer = ref[:i], ref[i+1:]
+	}
+
+	switch {
+	case file == "":
+		file = r.rootPath
+	case strings.Contains(file, "://"):
+		return nil, "", fmt.Errorf("remote reference %q is not supported", ref)
+	case !filepath.IsAbs(file):
+		file = filepath.Join(filepath.Dir(r.rootPath), file)
+	}
+
+	doc, err := r.document(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("reference %q: %v", ref, err)
+	}
+
+	target, err := evalJSONPointer(doc, pointer)
+	if err != nil {
+		return nil, "", fmt.Errorf("unresolved reference %q: %v", ref, err)
+	}
+
+	return target, file + "#" + pointer, nil
+}
+
+// document loads and caches a JSON or YAML document by absolute path
+func (r *swaggerResolver) document(path string) (map[string]interface{}, error) {
+	if doc, ok := r.docs[path]; ok {
+		return doc, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeGenericSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	absolutizeRefs(doc, path)
+	r.docs[path] = doc
+	return doc, nil
+}
+
+// absolutizeRefs rewrites every $ref in a document loaded from docPath to an
+// absolute file path, so it resolves the same way wherever it is used
+func absolutizeRefs(v interface{}, docPath string) {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := node["$ref"].(string); ok {
+			node["$ref"] = absoluteRef(ref, docPath)
+		}
+		for _, child := range node {
+			absolutizeRefs(child, docPath)
+		}
+	case []interface{}:
+		for _, child := range node {
+			absolutizeRefs(child, docPath)
+		}
+	}
+}
+
+// absoluteRef makes a reference found in docPath absolute
+func absoluteRef(ref, docPath string) string {
+	if strings.HasPrefix(ref, "#") {
+		return docPath + ref
+	}
+	file, fragment := ref, ""
+	if i := strings.Index(ref, "#"); i >= 0 {
+		file, fragment = ref[:i], ref[i:]
+	}
+	if strings.Contains(file, "://") || filepath.IsAbs(file) {
+		return ref
+	}
+	return filepath.Join(filepath.Dir(docPath), file) + fragment
+}
+
+// evalJSONPointer evaluates an RFC 6901 JSON pointer against a generic document
+func evalJSONPointer(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" || pointer == "/" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	current := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		token = strings.ReplaceAll(token, "~1", "/")
+		token = strings.ReplaceAll(token, "~0", "~")
+
+		switch node := current.(type) {
+		case map[string]interface{}:
+			next, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%q not found", token)
+			}
+			current = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("index %q out of range", token)
+			}
+			current = node[i]
+		default:
+			return nil, fmt.Errorf("%q not found", token)
+		}
+	}
+
+	return current, nil
+}
+
+// remarshal converts a generic value into a typed specification object
+func remarshal(v interface{}, out interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,403 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bodyFields lists the name, data type and requiredness of the body cases of
+// the only endpoint of a specification
+func bodyFields(t *testing.T, p SpecProcessor, path string) []string {
+	t.Helper()
+	endpoints, err := p.ProcessFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("%s: got %d endpoints, want 1", path, len(endpoints))
+	}
+	var fields []string
+	for _, c := range endpoints[0].Cases {
+		if c.ParamIn == "body" {
+			required := ""
+			if c.Required {
+				required = " required"
+			}
+			fields = append(fields, c.ParamName+" "+c.DataType+required)
+		}
+	}
+	return fields
+}
+
+func TestSwaggerBodyMatchesOpenAPI3(t *testing.T) {
+	const pet = `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}, "age": {"type": "integer"}}}`
+	tests := []struct {
+		name   string
+		schema string
+		want   []string
+	}{
+		{
+			name:   "top-level array of objects",
+			schema: `{"type": "array", "items": {"$ref": "#/definitions/Pet"}}`,
+			want:   []string{"body array[object] required", "body[].age integer", "body[].name string required"},
+		},
+		{
+			name:   "top-level array of strings",
+			schema: `{"type": "array", "items": {"type": "string"}}`,
+			want:   []string{"body array[string] required"},
+		},
+		{
+			name:   "object with properties",
+			schema: `{"$ref": "#/definitions/Pet"}`,
+			want:   []string{"age integer", "name string required"},
+		},
+	}
+
+	dir := t.TempDir()
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swagger := filepath.Join(dir, fmt.Sprintf("swagger%d.json", i))
+			writeFile(t, swagger, `{"swagger": "2.0", "info": {"title": "t", "version": "1"},
+				"paths": {"/pets": {"post": {"parameters": [{"name": "pets", "in": "body", "required": true, "schema": `+tt.schema+`}],
+					"responses": {"200": {"description": "ok"}}}}},
+				"definitions": {"Pet": `+pet+`}}`)
+			openapi := filepath.Join(dir, fmt.Sprintf("openapi%d.json", i))
+			writeFile(t, openapi, `{"openapi": "3.0.0", "info": {"title": "t", "version": "1"},
+				"paths": {"/pets": {"post": {"requestBody": {"required": true, "content": {"application/json": {"schema": `+
+				strings.ReplaceAll(tt.schema, "#/definitions/", "#/components/schemas/")+`}}},
+					"responses": {"200": {"description": "ok"}}}}},
+				"components": {"schemas": {"Pet": `+pet+`}}}`)
+
+			got := bodyFields(t, &Swagger2Processor{}, swagger)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Swagger 2.0 body fields = %q, want %q", got, tt.want)
+			}
+			if openapi3 := bodyFields(t, &OpenAPI3Processor{}, openapi); !reflect.DeepEqual(got, openapi3) {
+				t.Errorf("Swagger 2.0 body fields = %q, OpenAPI 3 = %q", got, openapi3)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSwaggerYAMLMatchesJSON(t *testing.T) {
+	const jsonSpec = `{"swagger": "2.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/pets/{id}": {"get": {"parameters": [
+			{"name": "id", "in": "path", "required": true, "type": "integer", "minimum": 1},
+			{"name": "tag", "in": "query", "type": "string", "enum": ["a", "b"]}],
+			"responses": {"200": {"description": "ok"}}}}}}`
+	const yamlSpec = `# Swagger 2.0 written in YAML
+swagger: 2.0
+info: {title: t, version: 1}
+x-id: &id {name: id, in: path, required: true, type: integer, minimum: 1}
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - *id
+        - name: tag
+          in: query
+          type: string
+          enum: [a, b]
+      responses:
+        200: {description: ok}
+`
+
+	dir := t.TempDir()
+	jsonPath, yamlPath := filepath.Join(dir, "swagger.json"), filepath.Join(dir, "swagger.yaml")
+	writeFile(t, jsonPath, jsonSpec)
+	writeFile(t, yamlPath, yamlSpec)
+
+	fromJSON, err := (&Swagger2Processor{}).ProcessFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := (&Swagger2Processor{}).ProcessFile(yamlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fromJSON) != 1 || len(fromJSON[0].Cases) != 2 {
+		t.Fatalf("JSON endpoints = %+v, want one endpoint with two parameters", fromJSON)
+	}
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("YAML endpoints = %+v, JSON endpoints = %+v", fromYAML, fromJSON)
+	}
+}
+
+func TestSwaggerReferences(t *testing.T) {
+	const pet = `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`
+	tests := []struct {
+		name     string
+		params   string            // the parameters of POST /pets
+		extra    string            // further top-level sections of the specification
+		files    map[string]string // other files next to the specification
+		want     []string
+		warnings []string // parts of the expected warnings, in order
+	}{
+		{
+			name:   "chain of references",
+			params: `[{"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Alias"}}]`,
+			extra:  `"definitions": {"Alias": {"$ref": "#/definitions/Pet"}, "Pet": ` + pet + `}`,
+			want:   []string{"name/body string required"},
+		},
+		{
+			name:   "relative file with a reference inside it",
+			params: `[{"name": "pet", "in": "body", "schema": {"$ref": "models/pet.json#/Pet"}}]`,
+			files: map[string]string{"models/pet.json": `{"Pet": {"type": "object", "properties": {
+				"owner": {"$ref": "#/Owner"}}},
+				"Owner": {"type": "object", "properties": {"id": {"type": "integer"}}}}`},
+			want: []string{"owner/body object", "owner.id/body integer"},
+		},
+		{
+			name:   "shared parameters",
+			params: `[{"$ref": "#/parameters/Limit"}, {"$ref": "#/parameters/Pet"}]`,
+			extra: `"parameters": {"Limit": {"name": "limit", "in": "query", "type": "integer"},
+				"Pet": {"name": "pet", "in": "body", "schema": {"$ref": "#/definitions/Pet"}}},
+				"definitions": {"Pet": ` + pet + `}`,
+			want: []string{"limit/query integer", "name/body string required"},
+		},
+		{
+			name:   "allOf",
+			params: `[{"name": "dog", "in": "body", "schema": {"$ref": "#/definitions/Dog"}}]`,
+			extra: `"definitions": {"Pet": ` + pet + `, "Dog": {"allOf": [{"$ref": "#/definitions/Pet"},
+				{"type": "object", "required": ["bark"], "properties": {"bark": {"type": "boolean"}}}]}}`,
+			want: []string{"bark/body boolean required", "name/body string required"},
+		},
+		{
+			name:   "reference cycle",
+			params: `[{"name": "a", "in": "body", "schema": {"$ref": "#/definitions/A"}}]`,
+			extra:  `"definitions": {"A": {"$ref": "#/definitions/B"}, "B": {"$ref": "#/definitions/A"}}`,
+			want:   []string{"a/body object"},
+		},
+		{
+			name: "unresolvable references",
+			params: `[{"$ref": "#/parameters/Missing"},
+				{"name": "pet", "in": "body", "schema": {"$ref": "missing.json#/Pet"}}]`,
+			want:     []string{"body/body object"},
+			warnings: []string{"#/parameters/Missing", "missing.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				writeFile(t, filepath.Join(dir, name), content)
+			}
+			extra := ""
+			if tt.extra != "" {
+				extra = ", " + tt.extra
+			}
+			path := filepath.Join(dir, "swagger.json")
+			writeFile(t, path, `{"swagger": "2.0", "info": {"title": "t", "version": "1"},
+				"paths": {"/pets": {"post": {"parameters": `+tt.params+`, "responses": {"200": {"description": "ok"}}}}}`+extra+`}`)
+
+			p := &Swagger2Processor{}
+			endpoints, err := p.ProcessFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(endpoints) != 1 {
+				t.Fatalf("got %d endpoints, want 1", len(endpoints))
+			}
+			var got []string
+			for _, c := range endpoints[0].Cases {
+				required := ""
+				if c.Required {
+					required = " required"
+				}
+				got = append(got, c.ParamName+"/"+c.ParamIn+" "+c.DataType+required)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cases = %q, want %q", got, tt.want)
+			}
+
+			warnings := p.Warnings()
+			if len(warnings) != len(tt.warnings) {
+				t.Fatalf("warnings = %q, want %d", warnings, len(tt.warnings))
+			}
+			for i, want := range tt.warnings {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("warning %d = %q, want it to mention %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSwaggerDiscriminatorVariants(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "swagger.json")
+	writeFile(t, path, `{"swagger": "2.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/shapes": {"post": {"parameters": [{"name": "shape", "in": "body", "schema": {"$ref": "#/definitions/Shape"}}],
+			"responses": {"200": {"description": "ok"}}}}},
+		"definitions": {
+			"Shape": {"type": "object", "required": ["kind"], "discriminator": "kind", "properties": {"kind": {"type": "string"}}},
+			"Circle": {"allOf": [{"$ref": "#/definitions/Shape"}, {"type": "object", "properties": {"r": {"type": "number"}}}]},
+			"Square": {"allOf": [{"$ref": "#/definitions/Shape"}, {"type": "object", "properties": {"side": {"type": "number"}}}]}}}`)
+	want := []string{
+		`group "" oneOf "kind": Circle=Circle Square=Square`,
+		"kind [Circle] string [Circle]",
+		"r [Circle] number []",
+		"kind [Square] string [Square]",
+		"side [Square] number []",
+	}
+
+	endpoints, err := (&Swagger2Processor{}).ProcessFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("got %d endpoints, want 1", len(endpoints))
+	}
+	if got := variantSummary(endpoints[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSwaggerCollectionFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "swagger.json")
+	writeFile(t, path, `{"swagger": "2.0", "info": {"title": "t", "version": "1"},
+		"paths": {"/items/{ids}": {"get": {"parameters": [
+			{"name": "ids", "in": "path", "required": true, "type": "array", "items": {"type": "integer"}},
+			{"name": "tags", "in": "query", "type": "array", "collectionFormat": "multi", "items": {"type": "string"}},
+			{"name": "sort", "in": "query", "type": "array", "collectionFormat": "pipes", "items": {"type": "string"}},
+			{"name": "X-Ids", "in": "header", "type": "array", "collectionFormat": "ssv", "items": {"type": "integer"}},
+			{"name": "q", "in": "query", "type": "string"}],
+			"responses": {"200": {"description": "ok"}}}}}}`)
+	want := map[string]string{
+		"ids":   "csv",
+		"tags":  "multi",
+		"sort":  "pipes",
+		"X-Ids": "ssv",
+		"q":     "",
+	}
+
+	endpoints, err := (&Swagger2Processor{}).ProcessFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(endpoints) != 1 {
+		t.Fatalf("got %d endpoints, want 1", len(endpoints))
+	}
+	if len(endpoints[0].Cases) != len(want) {
+		t.Fatalf("got %d parameters, want %d", len(endpoints[0].Cases), len(want))
+	}
+	for _, c := range endpoints[0].Cases {
+		if c.CollectionFormat != want[c.ParamName] {
+			t.Errorf("%s: got collectionFormat %q, want %q", c.ParamName, c.CollectionFormat, want[c.ParamName])
+		}
+		if c.Style != "" || c.Explode || c.AllowReserved {
+			t.Errorf("%s: got style %q, explode %v and allowReserved %v, want none", c.ParamName, c.Style, c.Explode, c.AllowReserved)
+		}
+	}
+}
+
+func TestSwaggerFormData(t *testing.T) {
+	const name = `{"name": "name", "in": "formData", "type": "string"}`
+	const upload = `{"name": "upload", "in": "formData", "type": "file", "required": true}`
+	tests := []struct {
+		name        string
+		consumes    string // document consumes
+		opConsumes  string // operation consumes
+		params      string
+		want        string // media type of the formData fields
+		wantUploads []string
+	}{
+		{name: "urlencoded by default", params: name, want: "application/x-www-form-urlencoded"},
+		{name: "multipart declared", opConsumes: `"multipart/form-data"`, params: name, want: "multipart/form-data"},
+		{name: "document consumes", consumes: `"multipart/form-data"`, params: name, want: "multipart/form-data"},
+		{name: "operation consumes win", consumes: `"multipart/form-data"`, opConsumes: `"application/x-www-form-urlencoded"`, params: name,
+			want: "application/x-www-form-urlencoded"},
+		{name: "multipart preferred", opConsumes: `"application/x-www-form-urlencoded", "multipart/form-data"`, params: name, want: "multipart/form-data"},
+		{name: "file without consumes", params: name + ", " + upload, want: "multipart/form-data", wantUploads: []string{"upload"}},
+		{name: "file despite urlencoded consumes", opConsumes: `"application/x-www-form-urlencoded"`, params: name + ", " + upload,
+			want: "multipart/form-data", wantUploads: []string{"upload"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "swagger.json")
+			writeFile(t, path, `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "consumes": [`+tt.consumes+`],
+				"paths": {"/upload": {"post": {"consumes": [`+tt.opConsumes+`], "parameters": [`+tt.params+`],
+					"responses": {"200": {"description": "ok"}}}}}}`)
+			endpoints, err := (&Swagger2Processor{}).ProcessFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(endpoints) != 1 {
+				t.Fatalf("got %d endpoints, want 1", len(endpoints))
+			}
+			ep := endpoints[0]
+			if !reflect.DeepEqual(ep.MediaTypes, []string{tt.want}) {
+				t.Errorf("media types %v, want [%s]", ep.MediaTypes, tt.want)
+			}
+			var uploads []string
+			for _, c := range ep.Cases {
+				if c.MediaType != tt.want {
+					t.Errorf("%s: media type %q, want %q", c.ParamName, c.MediaType, tt.want)
+				}
+				if c.DataType == "file" {
+					uploads = append(uploads, c.ParamName)
+				}
+			}
+			if !reflect.DeepEqual(uploads, tt.wantUploads) {
+				t.Errorf("file fields %v, want %v", uploads, tt.wantUploads)
+			}
+		})
+	}
+}
+
+func TestSwaggerServers(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string // document fields besides paths
+		op   string // operation fields besides responses
+		want []string
+	}{
+		{name: "no host or basePath"},
+		{name: "basePath only", doc: `"basePath": "/api/"`, want: []string{"/api /api"}},
+		{name: "https by default", doc: `"host": "api.example.com", "basePath": "/v1"`, want: []string{"https://api.example.com/v1 /v1"}},
+		{name: "document scheme", doc: `"host": "api.example.com", "schemes": ["http", "https"]`, want: []string{"http://api.example.com "}},
+		{name: "operation scheme wins", doc: `"host": "localhost:8080", "schemes": ["http"]`, op: `"schemes": ["https"], `,
+			want: []string{"https://localhost:8080 "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := tt.doc
+			if doc != "" {
+				doc += ", "
+			}
+			path := filepath.Join(t.TempDir(), "swagger.json")
+			writeFile(t, path, `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, `+doc+`
+				"paths": {"/pets": {"get": {`+tt.op+`"responses": {"200": {"description": "ok"}}}}}}`)
+			endpoints, err := (&Swagger2Processor{}).ProcessFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(endpoints) != 1 {
+				t.Fatalf("got %d endpoints, want 1", len(endpoints))
+			}
+			var got []string
+			for _, s := range endpoints[0].Servers {
+				got = append(got, s.URL+" "+s.BasePath)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
+	"sort"
 )
 
 // TestResult represents a single test case from JUnit XML
@@ -29,11 +30,20 @@ type TestSuites struct {
 	TestSuites []TestSuite `xml:"testsuite"`
 }
 
+// GeneratedTest is a generated test case with its input and expected outcome
+type GeneratedTest struct {
+	ID          string
+	Type        string // case category: valid, invalid, boundary_min, security, ...
+	Expected    string // accept or reject
+	Value       string // input value as JSON, "" when the case has none
+	Description string
+}
+
 // ValidationResult represents the comparison between generated and actual tests
 type ValidationResult struct {
-	Implemented []string // Tests that exist in both generated and actual
-	Missing     []string // Tests that are generated but not implemented
-	Extra       []string // Tests that exist in actual but weren't generated
+	Implemented []GeneratedTest // Tests that exist in both generated and actual
+	Missing     []GeneratedTest // Tests that are generated but not implemented
+	Extra       []string        // Tests that exist in actual but weren't generated
 }
 
 // Validator provides test validation functionality
@@ -69,8 +79,8 @@ func (v *Validator) LoadTestResults(filename string) ([]TestResult, error) {
 	return results, nil
 }
 
-// CompareTests compares generated test case IDs with actual test results
-func (v *Validator) CompareTests(generatedTestIDs []string, actualTests []TestResult) *ValidationResult {
+// CompareTests compares generated test cases with actual test results
+func (v *Validator) CompareTests(generatedTests []GeneratedTest, actualTests []TestResult) *ValidationResult {
 	result := &ValidationResult{}
 
 	// Create a map of actual test names for quick lookup
@@ -80,19 +90,19 @@ func (v *Validator) CompareTests(generatedTestIDs []string, actualTests []TestRe
 	}
 
 	// Check each generated test
-	for _, generatedID := range generatedTestIDs {
-		if actualTestMap[generatedID] {
-			result.Implemented = append(result.Implemented, generatedID)
+	for _, generated := range generatedTests {
+		if actualTestMap[generated.ID] {
+			result.Implemented = append(result.Implemented, generated)
 		} else {
-			result.Missing = append(result.Missing, generatedID)
+			result.Missing = append(result.Missing, generated)
 		}
 	}
 
 	// Find extra tests (tests that exist but weren't generated)
 	for _, test := range actualTests {
 		found := false
-		for _, generatedID := range generatedTestIDs {
-			if test.Name == generatedID {
+		for _, generated := range generatedTests {
+			if test.Name == generated.ID {
 				found = true
 				break
 			}
@@ -115,14 +125,14 @@ func (v *Validator) PrintReport(result *ValidationResult) {
 	if len(result.Implemented) > 0 {
 		fmt.Println("\n✅ IMPLEMENTED TESTS:")
 		for _, test := range result.Implemented {
-			fmt.Printf("  - %s\n", test)
+			fmt.Printf("  - %s\n", formatGeneratedTest(test))
 		}
 	}
 
 	if len(result.Missing) > 0 {
 		fmt.Println("\n❌ MISSING TESTS:")
 		for _, test := range result.Missing {
-			fmt.Printf("  - %s\n", test)
+			fmt.Printf("  - %s\n", formatGeneratedTest(test))
 		}
 	}
 
@@ -133,10 +143,77 @@ func (v *Validator) PrintReport(result *ValidationResult) {
 		}
 	}
 
+	printUntestedResponses(result)
+	printTypeCoverage(result)
+
 	totalGenerated := len(result.Implemented) + len(result.Missing)
 	if totalGenerated > 0 {
 		coverage := float64(len(result.Implemented)) / float64(totalGenerated) * 100
 		fmt.Printf("\n📊 Coverage: %.1f%% (%d/%d generated tests implemented)\n",
 			coverage, len(result.Implemented), totalGenerated)
 	}
-}
\ No newline at end of file
+}
+
+// formatGeneratedTest renders a generated test as "id [expected] value"
+func formatGeneratedTest(test GeneratedTest) string {
+	line := test.ID
+	if test.Expected != "" {
+		line += " [" + test.Expected + "]"
+	}
+	if test.Value != "" {
+		line += " " + test.Value
+	}
+	return line
+}
+
+// printTypeCoverage prints the coverage of each test case type, so categories
+// such as security can be tracked on their own
+func printTypeCoverage(result *ValidationResult) {
+	implemented := map[string]int{}
+	total := map[string]int{}
+	for _, test := range result.Implemented {
+		implemented[test.Type]++
+		total[test.Type]++
+	}
+	for _, test := range result.Missing {
+		total[test.Type]++
+	}
+	if len(total) == 0 {
+		return
+	}
+
+	types := make([]string, 0, len(total))
+	for t := range total {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	fmt.Println("\n📋 COVERAGE BY TYPE:")
+	for _, t := range types {
+		name := t
+		if name == "" {
+			name = "other"
+		}
+		fmt.Printf("  - %s: %d/%d (%.1f%%)\n", name, implemented[t], total[t],
+			float64(implemented[t])/float64(total[t])*100)
+	}
+}
+
+// printUntestedResponses names the documented responses no test reaches, e.g.
+// "404 response of GET /pet/{petId}: Pet not found"
+func printUntestedResponses(result *ValidationResult) {
+	var untested []GeneratedTest
+	for _, test := range result.Missing {
+		if test.Type == "response" {
+			untested = append(untested, test)
+		}
+	}
+	if len(untested) == 0 {
+		return
+	}
+
+	fmt.Println("\n🚦 UNTESTED RESPONSES:")
+	for _, test := range untested {
+		fmt.Printf("  - %s is untested (%s)\n", test.Description, test.ID)
+	}
+}